@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/atselvan/go-utils/utils/errors"
 	"github.com/atselvan/go-utils/utils/logger"
 )
@@ -13,6 +15,27 @@ type ServerConfig struct {
 	LogLevel              string `mapstructure:"SERVER_LOG_LEVEL"`
 	StaticFilesRoot       string `mapstructure:"STATIC_FILES_ROOT"`
 	HTMLTemplateFilesRoot string `mapstructure:"HTML_TEMPLATE_FILES_ROOT"`
+
+	CORSAllowedOrigins   []string      `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	CORSAllowedMethods   []string      `mapstructure:"CORS_ALLOWED_METHODS"`
+	CORSAllowedHeaders   []string      `mapstructure:"CORS_ALLOWED_HEADERS"`
+	CORSExposedHeaders   []string      `mapstructure:"CORS_EXPOSED_HEADERS"`
+	CORSAllowCredentials bool          `mapstructure:"CORS_ALLOW_CREDENTIALS"`
+	CORSMaxAge           time.Duration `mapstructure:"CORS_MAX_AGE"`
+
+	HSTSMaxAge            time.Duration `mapstructure:"HSTS_MAX_AGE"`
+	FrameOptions          string        `mapstructure:"FRAME_OPTIONS"`
+	ContentSecurityPolicy string        `mapstructure:"CONTENT_SECURITY_POLICY"`
+	ReferrerPolicy        string        `mapstructure:"REFERRER_POLICY"`
+
+	ReadTimeout        time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ReadHeaderTimeout  time.Duration `mapstructure:"SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout       time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout        time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	RequestTimeout     time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	MaxRequestBodySize int64         `mapstructure:"MAX_REQUEST_BODY_SIZE"`
+
+	SwaggerDisabled bool `mapstructure:"SWAGGER_DISABLED"`
 }
 
 // LoadServerConfig loads configuration from the environment and returns a ServerConfig instance.
@@ -23,6 +46,11 @@ type ServerConfig struct {
 //	ServerConfig.Protocol =  https
 //	ServerConfig.StaticFilesRoot = /
 //	ServerConfig.HTMLTemplateFilesRoot = /
+//	ServerConfig.ReadTimeout = 30s
+//	ServerConfig.ReadHeaderTimeout = 10s
+//	ServerConfig.WriteTimeout = 30s
+//	ServerConfig.IdleTimeout = 120s
+//	ServerConfig.MaxRequestBodySize = 10 MiB
 func LoadServerConfig() (*ServerConfig, *errors.Error) {
 	cnf := new(ServerConfig)
 	if err := Load(cnf); err != nil {
@@ -40,5 +68,20 @@ func LoadServerConfig() (*ServerConfig, *errors.Error) {
 	if cnf.HTMLTemplateFilesRoot == "" {
 		cnf.HTMLTemplateFilesRoot = "/"
 	}
+	if cnf.ReadTimeout == 0 {
+		cnf.ReadTimeout = 30 * time.Second
+	}
+	if cnf.ReadHeaderTimeout == 0 {
+		cnf.ReadHeaderTimeout = 10 * time.Second
+	}
+	if cnf.WriteTimeout == 0 {
+		cnf.WriteTimeout = 30 * time.Second
+	}
+	if cnf.IdleTimeout == 0 {
+		cnf.IdleTimeout = 120 * time.Second
+	}
+	if cnf.MaxRequestBodySize == 0 {
+		cnf.MaxRequestBodySize = 10 << 20
+	}
 	return cnf, nil
 }
@@ -1,8 +1,10 @@
 package config
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 var (
@@ -13,6 +15,19 @@ SERVER_PORT=8000
 SERVER_LOG_LEVEL=INFO
 STATIC_FILES_ROOT=static
 HTML_TEMPLATE_FILES_ROOT=templates
+CORS_ALLOWED_ORIGINS=https://*.example.com,https://example.com
+CORS_ALLOWED_METHODS=GET,POST
+CORS_ALLOW_CREDENTIALS=true
+CORS_MAX_AGE=10m
+HSTS_MAX_AGE=24h
+FRAME_OPTIONS=SAMEORIGIN
+CONTENT_SECURITY_POLICY=default-src 'self'
+REFERRER_POLICY=no-referrer
+SERVER_READ_TIMEOUT=5s
+SERVER_WRITE_TIMEOUT=1m
+REQUEST_TIMEOUT=20s
+MAX_REQUEST_BODY_SIZE=1024
+SWAGGER_DISABLED=true
 `
 
 	testServerConfigOnlyRequired = `# Configuration
@@ -54,6 +69,21 @@ func TestLoadServerConfig(t *testing.T) {
 		assert.Equal(t, "INFO", cnf.LogLevel)
 		assert.Equal(t, "static", cnf.StaticFilesRoot)
 		assert.Equal(t, "templates", cnf.HTMLTemplateFilesRoot)
+		assert.Equal(t, []string{"https://*.example.com", "https://example.com"}, cnf.CORSAllowedOrigins)
+		assert.Equal(t, []string{"GET", "POST"}, cnf.CORSAllowedMethods)
+		assert.True(t, cnf.CORSAllowCredentials)
+		assert.Equal(t, 10*time.Minute, cnf.CORSMaxAge)
+		assert.Equal(t, 24*time.Hour, cnf.HSTSMaxAge)
+		assert.Equal(t, "SAMEORIGIN", cnf.FrameOptions)
+		assert.Equal(t, "default-src 'self'", cnf.ContentSecurityPolicy)
+		assert.Equal(t, "no-referrer", cnf.ReferrerPolicy)
+		assert.Equal(t, 5*time.Second, cnf.ReadTimeout)
+		assert.Equal(t, 10*time.Second, cnf.ReadHeaderTimeout)
+		assert.Equal(t, time.Minute, cnf.WriteTimeout)
+		assert.Equal(t, 120*time.Second, cnf.IdleTimeout)
+		assert.Equal(t, 20*time.Second, cnf.RequestTimeout)
+		assert.Equal(t, int64(1024), cnf.MaxRequestBodySize)
+		assert.True(t, cnf.SwaggerDisabled)
 	})
 
 	t.Run("only required", func(t *testing.T) {
@@ -69,6 +99,11 @@ func TestLoadServerConfig(t *testing.T) {
 		assert.Equal(t, cnf.LogLevel, "")
 		assert.Equal(t, cnf.StaticFilesRoot, "/")
 		assert.Equal(t, cnf.HTMLTemplateFilesRoot, "/")
+		assert.Equal(t, cnf.ReadTimeout, 30*time.Second)
+		assert.Equal(t, cnf.WriteTimeout, 30*time.Second)
+		assert.Equal(t, cnf.RequestTimeout, time.Duration(0))
+		assert.Equal(t, cnf.MaxRequestBodySize, int64(10<<20))
+		assert.False(t, cnf.SwaggerDisabled)
 	})
 
 	t.Run("missing required", func(t *testing.T) {
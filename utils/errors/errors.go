@@ -24,6 +24,7 @@ const (
 	ErrCodeInvalidPassword               = "PASSWORD_INVALID"
 	ErrCodePasswordEncryptionError       = "PASSWORD_ENCRYPTION_FAILED"
 	ErrCodePasswordDecryptionError       = "PASSWORD_DECRYPTION_FAILED"
+	ErrCodePasswordHashError             = "PASSWORD_HASH_FAILED"
 	ErrCodeRegexCompileError             = "REGEX_COMPILE_ERROR"
 	ErrCodeJSONMarshalError              = "JSON_MARSHAL_ERROR"
 	ErrCodeJSONUnmarshalError            = "JSON_UNMARSHAL_ERROR"
@@ -45,6 +46,17 @@ const (
 	ErrCodeSubjectTokenTypeInvalid       = "SUBJECT_TOKEN_TYPE_INVALID"
 	ErrCodeSubjectUnauthenticated        = "SUBJECT_UNAUTHENTICATED"
 	ErrCodeSubjectNotAllowed             = "SUBJECT_NOT_ALLOWED"
+	ErrCodeInvalidSigningKey             = "SIGNING_KEY_INVALID"
+	ErrCodeRateLimitExceeded             = "RATE_LIMIT_EXCEEDED"
+	ErrCodeRequestTooLarge               = "REQUEST_TOO_LARGE"
+	ErrCodeRequestTimeout                = "REQUEST_TIMEOUT"
+	ErrCodeInvalidOpenApiSpec            = "OPENAPI_SPEC_INVALID"
+	ErrCodeInvalidResponse               = "RESPONSE_INVALID"
+	ErrCodeInvalidStructTag              = "STRUCT_TAG_INVALID"
+	ErrCodeNotAcceptable                 = "NOT_ACCEPTABLE"
+	ErrCodeUpstreamUnavailable           = "UPSTREAM_UNAVAILABLE"
+	ErrCodeUpstreamError                 = "UPSTREAM_ERROR"
+	ErrCodeCircuitOpen                   = "CIRCUIT_OPEN"
 )
 
 var (
@@ -67,6 +79,7 @@ var (
 		ErrCodeInvalidPassword:               "Password should be at least 8 characters long with at least one number, one uppercase letter, one lowercase letter and one special character",
 		ErrCodePasswordEncryptionError:       "Password encryption errors: %v",
 		ErrCodePasswordDecryptionError:       "Password decryption errors: %v",
+		ErrCodePasswordHashError:             "Password hashing errors: %v",
 		ErrCodeRegexCompileError:             "Unable to compile regex : %v",
 		ErrCodeJSONMarshalError:              "JSON marshal errors : %v",
 		ErrCodeJSONUnmarshalError:            "JSON unmarshal errors : %v",
@@ -87,6 +100,17 @@ var (
 		ErrCodeSubjectTokenTypeInvalid:       "Value of header Subject-Token-Type (%s) must be one of %v",
 		ErrCodeSubjectUnauthenticated:        "Subject token is not valid",
 		ErrCodeSubjectNotAllowed:             "Insufficient access",
+		ErrCodeInvalidSigningKey:             "Unable to parse signing key : %v",
+		ErrCodeRateLimitExceeded:             "Rate limit exceeded, retry after %d seconds",
+		ErrCodeRequestTooLarge:               "Request body must not exceed %d bytes",
+		ErrCodeRequestTimeout:                "Request could not be processed within %v",
+		ErrCodeInvalidOpenApiSpec:            "OpenAPI spec is not valid : %v",
+		ErrCodeInvalidResponse:               "Response does not match the API specification : %v",
+		ErrCodeInvalidStructTag:              "Unable to apply the %s tag of field '%s' : %v",
+		ErrCodeNotAcceptable:                 "None of the accepted media types is supported, supported media types : %v",
+		ErrCodeUpstreamUnavailable:           "Unable to call the upstream service : %v",
+		ErrCodeUpstreamError:                 "Upstream service responded with status %d",
+		ErrCodeCircuitOpen:                   "Circuit breaker of '%s' is open, the upstream service is not called",
 	}
 )
 
@@ -96,10 +120,12 @@ type Errors struct {
 }
 
 // Error represents the error information.
+// Field is the name of the parameter or the path of the payload field the error relates to, if any.
 type Error struct {
 	Code    string `json:"code"`
 	Status  int    `json:"status"`
 	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
 	TraceId string `json:"traceId"`
 }
 
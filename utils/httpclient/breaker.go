@@ -0,0 +1,392 @@
+package httpclient
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	CircuitStateClosed   = "closed"
+	CircuitStateOpen     = "open"
+	CircuitStateHalfOpen = "half-open"
+
+	FieldCircuit   = "circuit"
+	FieldFromState = "from-state"
+	FieldToState   = "to-state"
+
+	DefaultFailureRatio        = 0.5
+	DefaultMinRequests         = 20
+	DefaultConsecutiveFailures = 5
+	DefaultCoolDown            = 30 * time.Second
+	DefaultBreakerWindow       = time.Minute
+	DefaultHalfOpenRequests    = 1
+
+	circuitStateChangedMsg = "Circuit breaker state changed"
+	circuitsNotClosedMsg   = "circuits are not closed: %s"
+)
+
+type (
+	// BreakerConfig represents the configuration of a CircuitBreaker.
+	BreakerConfig struct {
+		FailureRatio        float64
+		MinRequests         int
+		ConsecutiveFailures int
+		CoolDown            time.Duration
+		Window              time.Duration
+		HalfOpenRequests    int
+		IsFailure           func(resp *http.Response, err error) bool
+		Key                 func(req *http.Request) string
+	}
+
+	// BreakerOption is an option that can be used to configure a CircuitBreaker.
+	BreakerOption func(config *BreakerConfig)
+
+	// CircuitBreaker stops calling an upstream service that keeps failing, to give it time to recover.
+	// A circuit is kept per key, by default the host of the request.
+	// A closed circuit lets all requests through and opens when the failure thresholds are reached.
+	// An open circuit rejects all requests until the cool-down period has passed and then becomes half-open.
+	// A half-open circuit lets a limited number of trial requests through. It closes if the trial requests succeed
+	// and opens again if one of them fails.
+	CircuitBreaker struct {
+		config *BreakerConfig
+		now    func() time.Time
+
+		mu       sync.Mutex
+		circuits map[string]*circuit
+		changes  []stateChange
+	}
+
+	// CircuitOpenError is returned by the requests that are rejected because the circuit is open.
+	CircuitOpenError struct {
+		Key string
+	}
+
+	// circuit represents the state of a single circuit.
+	// The generation changes with every state change, to ignore the outcomes of the requests that were allowed in a
+	// previous state.
+	circuit struct {
+		state               string
+		generation          uint64
+		openedAt            time.Time
+		windowStart         time.Time
+		requests            int
+		failures            int
+		consecutiveFailures int
+		trials              int
+		trialSuccesses      int
+	}
+
+	// stateChange represents a state change of a circuit, which is logged after the lock is released.
+	stateChange struct {
+		key  string
+		from string
+		to   string
+	}
+
+	// breakerTransport is a http.RoundTripper that guards the requests with a CircuitBreaker.
+	breakerTransport struct {
+		breaker *CircuitBreaker
+		next    http.RoundTripper
+	}
+)
+
+// WithFailureRatio is a CircuitBreaker option that opens a closed circuit when the ratio of failed requests within the
+// window reaches the ratio, after at least minRequests requests. The ratio is disabled if it is 0.
+// Default is a ratio of 0.5 with 20 requests.
+func WithFailureRatio(ratio float64, minRequests int) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.FailureRatio = ratio
+		config.MinRequests = minRequests
+	}
+}
+
+// WithConsecutiveFailures is a CircuitBreaker option that opens a closed circuit after the number of consecutive
+// failed requests. The number is disabled if it is 0. Default is 5 consecutive failures.
+func WithConsecutiveFailures(failures int) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.ConsecutiveFailures = failures
+	}
+}
+
+// WithCoolDown is a CircuitBreaker option that defines how long a circuit stays open before trial requests are let
+// through. Default cool-down is 30 seconds.
+func WithCoolDown(coolDown time.Duration) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.CoolDown = coolDown
+	}
+}
+
+// WithBreakerWindow is a CircuitBreaker option that defines the period after which the counts of a closed circuit are
+// reset. Default window is 1 minute.
+func WithBreakerWindow(window time.Duration) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.Window = window
+	}
+}
+
+// WithHalfOpenRequests is a CircuitBreaker option that defines the number of trial requests of a half-open circuit,
+// which must all succeed to close the circuit. Default is 1 request.
+func WithHalfOpenRequests(requests int) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.HalfOpenRequests = requests
+	}
+}
+
+// WithFailureCondition is a CircuitBreaker option that defines which requests are failures.
+// By default, requests that failed without a response and responses with a 5xx status are failures.
+func WithFailureCondition(isFailure func(resp *http.Response, err error) bool) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.IsFailure = isFailure
+	}
+}
+
+// WithBreakerKey is a CircuitBreaker option that defines the key of the circuit of a request.
+// Default key is the host of the request.
+func WithBreakerKey(key func(req *http.Request) string) BreakerOption {
+	return func(config *BreakerConfig) {
+		config.Key = key
+	}
+}
+
+// NewCircuitBreaker returns a new CircuitBreaker that is configured with the options.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	config := &BreakerConfig{
+		FailureRatio:        DefaultFailureRatio,
+		MinRequests:         DefaultMinRequests,
+		ConsecutiveFailures: DefaultConsecutiveFailures,
+		CoolDown:            DefaultCoolDown,
+		Window:              DefaultBreakerWindow,
+		HalfOpenRequests:    DefaultHalfOpenRequests,
+		IsFailure:           isFailure,
+		Key:                 func(req *http.Request) string { return req.URL.Host },
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.HalfOpenRequests < 1 {
+		config.HalfOpenRequests = 1
+	}
+	return &CircuitBreaker{
+		config:   config,
+		now:      time.Now,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// Error returns the message of the error.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(errors.ErrMsg[errors.ErrCodeCircuitOpen], e.Key)
+}
+
+// Wrap guards every attempt of the requests of the resty client with the circuit breaker, by wrapping the transport
+// of the client. Wrap must be called after the transport of the client is set.
+// Rejected requests fail with a CircuitOpenError, which is not retried and is turned into a ErrCodeCircuitOpen error
+// by ResponseError.
+func (b *CircuitBreaker) Wrap(client *resty.Client) *resty.Client {
+	return client.SetTransport(b.Transport(client.GetClient().Transport))
+}
+
+// Transport returns a http.RoundTripper that guards the requests of the next http.RoundTripper with the circuit
+// breaker. http.DefaultTransport is used if next is nil.
+func (b *CircuitBreaker) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &breakerTransport{breaker: b, next: next}
+}
+
+// RoundTrip executes the request if the circuit of the request allows it and records the outcome.
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := t.breaker.config.Key(req)
+	generation, ok := t.breaker.allow(key)
+	if !ok {
+		return nil, &CircuitOpenError{Key: key}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil && stderrors.Is(err, context.Canceled) {
+		// the request was cancelled by the caller, which says nothing about the health of the upstream service
+		t.breaker.release(key, generation)
+		return resp, err
+	}
+	t.breaker.record(key, generation, !t.breaker.config.IsFailure(resp, err))
+	return resp, err
+}
+
+// State returns the state of the circuit of the key. Unknown circuits are closed.
+func (b *CircuitBreaker) State(key string) string {
+	b.mu.Lock()
+	defer b.unlock()
+	if c, ok := b.circuits[key]; ok {
+		b.refresh(key, c)
+		return c.state
+	}
+	return CircuitStateClosed
+}
+
+// States returns the states of all circuits by key.
+func (b *CircuitBreaker) States() map[string]string {
+	b.mu.Lock()
+	defer b.unlock()
+	states := make(map[string]string, len(b.circuits))
+	for key, c := range b.circuits {
+		b.refresh(key, c)
+		states[key] = c.state
+	}
+	return states
+}
+
+// HealthCheck is a httputil.HealthCheckFunc that fails if one of the circuits is not closed.
+// The check should be registered as a non-critical check, see httputil.NonCritical, because an upstream service
+// that is down should not take this service out of rotation.
+func (b *CircuitBreaker) HealthCheck(_ context.Context) error {
+	var open []string
+	for key, state := range b.States() {
+		if state != CircuitStateClosed {
+			open = append(open, key+" ("+state+")")
+		}
+	}
+	if len(open) == 0 {
+		return nil
+	}
+	sort.Strings(open)
+	return fmt.Errorf(circuitsNotClosedMsg, strings.Join(open, ", "))
+}
+
+// allow checks if a request is allowed by the circuit of the key and returns the generation of the circuit.
+func (b *CircuitBreaker) allow(key string) (uint64, bool) {
+	b.mu.Lock()
+	defer b.unlock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{state: CircuitStateClosed, windowStart: b.now()}
+		b.circuits[key] = c
+	}
+	b.refresh(key, c)
+
+	switch c.state {
+	case CircuitStateOpen:
+		return c.generation, false
+	case CircuitStateHalfOpen:
+		if c.trials >= b.config.HalfOpenRequests {
+			return c.generation, false
+		}
+		c.trials++
+	}
+	return c.generation, true
+}
+
+// release releases a request of the circuit of the key without recording its outcome.
+func (b *CircuitBreaker) release(key string, generation uint64) {
+	b.mu.Lock()
+	defer b.unlock()
+	if c, ok := b.circuits[key]; ok && c.generation == generation && c.state == CircuitStateHalfOpen && c.trials > 0 {
+		c.trials--
+	}
+}
+
+// record records the outcome of a request of the circuit of the key.
+// The outcome is ignored if the state of the circuit changed after the request was allowed.
+func (b *CircuitBreaker) record(key string, generation uint64, success bool) {
+	b.mu.Lock()
+	defer b.unlock()
+	c, ok := b.circuits[key]
+	if !ok || c.generation != generation {
+		return
+	}
+
+	switch c.state {
+	case CircuitStateClosed:
+		c.requests++
+		if success {
+			c.consecutiveFailures = 0
+			return
+		}
+		c.failures++
+		c.consecutiveFailures++
+		if b.config.ConsecutiveFailures > 0 && c.consecutiveFailures >= b.config.ConsecutiveFailures ||
+			b.config.FailureRatio > 0 && c.requests >= b.config.MinRequests &&
+				float64(c.failures)/float64(c.requests) >= b.config.FailureRatio {
+			b.transition(key, c, CircuitStateOpen)
+		}
+	case CircuitStateHalfOpen:
+		if !success {
+			b.transition(key, c, CircuitStateOpen)
+			return
+		}
+		c.trialSuccesses++
+		if c.trialSuccesses >= b.config.HalfOpenRequests {
+			b.transition(key, c, CircuitStateClosed)
+		}
+	}
+}
+
+// refresh moves an open circuit to half-open after the cool-down period and resets the counts of a closed circuit
+// after the window.
+func (b *CircuitBreaker) refresh(key string, c *circuit) {
+	now := b.now()
+	switch c.state {
+	case CircuitStateOpen:
+		if now.Sub(c.openedAt) >= b.config.CoolDown {
+			b.transition(key, c, CircuitStateHalfOpen)
+		}
+	case CircuitStateClosed:
+		if b.config.Window > 0 && now.Sub(c.windowStart) >= b.config.Window {
+			c.reset(now)
+		}
+	}
+}
+
+// transition changes the state of the circuit. The change is logged when the lock is released, see unlock.
+func (b *CircuitBreaker) transition(key string, c *circuit, state string) {
+	b.changes = append(b.changes, stateChange{key: key, from: c.state, to: state})
+	c.state = state
+	c.generation++
+	c.reset(b.now())
+	if state == CircuitStateOpen {
+		c.openedAt = b.now()
+	}
+}
+
+// unlock releases the lock and logs the state changes that were made while the lock was held.
+func (b *CircuitBreaker) unlock() {
+	changes := b.changes
+	b.changes = nil
+	b.mu.Unlock()
+
+	for _, change := range changes {
+		fields := []zap.Field{
+			zap.String(FieldCircuit, change.key), zap.String(FieldFromState, change.from), zap.String(FieldToState, change.to),
+		}
+		if change.to == CircuitStateOpen {
+			logger.Warn(circuitStateChangedMsg, fields...)
+		} else {
+			logger.Info(circuitStateChangedMsg, fields...)
+		}
+	}
+}
+
+// reset resets the counts of the circuit.
+func (c *circuit) reset(now time.Time) {
+	c.windowStart = now
+	c.requests = 0
+	c.failures = 0
+	c.consecutiveFailures = 0
+	c.trials = 0
+	c.trialSuccesses = 0
+}
+
+// isFailure is the default failure condition of a CircuitBreaker.
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp == nil || resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,247 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/logger/loggertest"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const (
+	testHost      = "test.com"
+	testOtherHost = "https://other.com"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newBreakerClient(opts ...BreakerOption) (*resty.Client, *CircuitBreaker, *testClock) {
+	clock := &testClock{now: time.Now()}
+	breaker := NewCircuitBreaker(opts...)
+	breaker.now = clock.Now
+
+	client := New(WithBaseURL(testBaseURL), WithRetry(0, 0, 0))
+	httpmock.ActivateNonDefault(client.GetClient())
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/ok", httpmock.NewStringResponder(http.StatusOK, "OK"))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/down", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/not-found", httpmock.NewStringResponder(http.StatusNotFound, ""))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/failure", httpmock.NewErrorResponder(fmt.Errorf("connection refused")))
+	httpmock.RegisterResponder(http.MethodGet, testOtherHost+"/ok", httpmock.NewStringResponder(http.StatusOK, "OK"))
+	return breaker.Wrap(client), breaker, clock
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	recorder := loggertest.New(t)
+	client, breaker, _ := newBreakerClient(WithConsecutiveFailures(3), WithFailureRatio(0, 0))
+	defer httpmock.DeactivateAndReset()
+
+	_, _ = client.R().Get("/down")
+	_, _ = client.R().Get("/failure")
+	_, _ = client.R().Get("/ok")
+	_, _ = client.R().Get("/not-found")
+	assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.R().Get("/down")
+	}
+	assert.Equal(t, CircuitStateOpen, breaker.State(testHost))
+	recorder.AssertLogged(zap.WarnLevel, circuitStateChangedMsg,
+		zap.String(FieldCircuit, testHost), zap.String(FieldFromState, CircuitStateClosed), zap.String(FieldToState, CircuitStateOpen))
+
+	calls := httpmock.GetTotalCallCount()
+	resp, err := client.R().Get("/ok")
+	var openErr *CircuitOpenError
+	assert.ErrorAs(t, err, &openErr)
+	assert.Equal(t, testHost, openErr.Key)
+	assert.Equal(t, calls, httpmock.GetTotalCallCount())
+	assert.Equal(t, &errors.Error{
+		Code:    errors.ErrCodeCircuitOpen,
+		Status:  http.StatusServiceUnavailable,
+		Message: "Circuit breaker of 'test.com' is open, the upstream service is not called",
+	}, ResponseError(resp, err))
+
+	t.Run("per host", func(t *testing.T) {
+		resp, err := client.R().Get(testOtherHost + "/ok")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, CircuitStateClosed, breaker.State("other.com"))
+	})
+}
+
+func TestCircuitBreaker_FailureRatio(t *testing.T) {
+	client, breaker, clock := newBreakerClient(WithFailureRatio(0.5, 4), WithConsecutiveFailures(0),
+		WithBreakerWindow(time.Minute))
+	defer httpmock.DeactivateAndReset()
+
+	_, _ = client.R().Get("/down")
+	_, _ = client.R().Get("/down")
+	_, _ = client.R().Get("/ok")
+	assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+
+	// the counts are reset after the window
+	clock.Add(time.Minute)
+	_, _ = client.R().Get("/down")
+	_, _ = client.R().Get("/ok")
+	_, _ = client.R().Get("/ok")
+	assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+
+	_, _ = client.R().Get("/down")
+	assert.Equal(t, CircuitStateOpen, breaker.State(testHost))
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	recorder := loggertest.New(t)
+	client, breaker, clock := newBreakerClient(WithConsecutiveFailures(1), WithCoolDown(10*time.Second),
+		WithHalfOpenRequests(2))
+	defer httpmock.DeactivateAndReset()
+
+	_, _ = client.R().Get("/down")
+	assert.Equal(t, CircuitStateOpen, breaker.State(testHost))
+
+	clock.Add(9 * time.Second)
+	assert.Equal(t, CircuitStateOpen, breaker.State(testHost))
+	clock.Add(time.Second)
+	assert.Equal(t, CircuitStateHalfOpen, breaker.State(testHost))
+
+	t.Run("failed trial", func(t *testing.T) {
+		_, err := client.R().Get("/failure")
+		assert.Error(t, err)
+		assert.Equal(t, CircuitStateOpen, breaker.State(testHost))
+	})
+
+	t.Run("limited trials", func(t *testing.T) {
+		clock.Add(10 * time.Second)
+		generation, ok := breaker.allow(testHost)
+		assert.True(t, ok)
+		_, ok = breaker.allow(testHost)
+		assert.True(t, ok)
+		_, ok = breaker.allow(testHost)
+		assert.False(t, ok)
+		breaker.release(testHost, generation)
+		breaker.release(testHost, generation)
+	})
+
+	t.Run("successful trials", func(t *testing.T) {
+		_, _ = client.R().Get("/ok")
+		assert.Equal(t, CircuitStateHalfOpen, breaker.State(testHost))
+		_, _ = client.R().Get("/ok")
+		assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+		recorder.AssertLogged(zap.InfoLevel, circuitStateChangedMsg,
+			zap.String(FieldFromState, CircuitStateHalfOpen), zap.String(FieldToState, CircuitStateClosed))
+	})
+}
+
+func TestCircuitBreaker_Generation(t *testing.T) {
+	client, breaker, clock := newBreakerClient(WithConsecutiveFailures(1), WithCoolDown(10*time.Second))
+	defer httpmock.DeactivateAndReset()
+
+	// the outcomes of the requests that were allowed by the closed circuit are ignored by the half-open circuit
+	closed, _ := breaker.allow(testHost)
+	_, _ = client.R().Get("/down")
+	clock.Add(10 * time.Second)
+	assert.Equal(t, CircuitStateHalfOpen, breaker.State(testHost))
+	breaker.record(testHost, closed, true)
+	assert.Equal(t, CircuitStateHalfOpen, breaker.State(testHost))
+	breaker.record(testHost, closed, false)
+	assert.Equal(t, CircuitStateHalfOpen, breaker.State(testHost))
+
+	// the trial of the half-open circuit is not released by a request of the closed circuit
+	halfOpen, ok := breaker.allow(testHost)
+	assert.True(t, ok)
+	breaker.release(testHost, closed)
+	_, ok = breaker.allow(testHost)
+	assert.False(t, ok)
+
+	breaker.record(testHost, halfOpen, true)
+	assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+}
+
+func TestCircuitBreaker_LogOutsideLock(t *testing.T) {
+	var states map[string]string
+	core, _ := observer.New(zap.InfoLevel)
+	client, breaker, _ := newBreakerClient(WithConsecutiveFailures(1))
+	defer httpmock.DeactivateAndReset()
+	defer logger.ReplaceLogger(zap.New(core, zap.Hooks(func(zapcore.Entry) error {
+		// the breaker would deadlock if the state change was logged while the lock is held
+		states = breaker.States()
+		return nil
+	})))()
+
+	_, _ = client.R().Get("/down")
+	assert.Equal(t, map[string]string{testHost: CircuitStateOpen}, states)
+}
+
+func TestCircuitBreaker_Retry(t *testing.T) {
+	breaker := NewCircuitBreaker(WithConsecutiveFailures(2))
+	client := New(WithBaseURL(testBaseURL), WithRetry(5, time.Millisecond, time.Millisecond))
+	httpmock.ActivateNonDefault(client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/down", httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	breaker.Wrap(client)
+
+	resp, err := client.R().Get("/down")
+	assert.Equal(t, errors.ErrCodeCircuitOpen, ResponseError(resp, err).Code)
+	assert.Equal(t, 3, resp.Request.Attempt)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestCircuitBreaker_Cancelled(t *testing.T) {
+	client, breaker, _ := newBreakerClient(WithConsecutiveFailures(1))
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/cancelled", httpmock.NewErrorResponder(context.Canceled))
+	_, err := client.R().Get("/cancelled")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, CircuitStateClosed, breaker.State(testHost))
+}
+
+func TestCircuitBreaker_HealthCheck(t *testing.T) {
+	client, breaker, _ := newBreakerClient(WithConsecutiveFailures(1))
+	defer httpmock.DeactivateAndReset()
+
+	assert.NoError(t, breaker.HealthCheck(context.Background()))
+	assert.Empty(t, breaker.States())
+
+	_, _ = client.R().Get(testOtherHost + "/ok")
+	assert.NoError(t, breaker.HealthCheck(context.Background()))
+
+	_, _ = client.R().Get("/down")
+	assert.Equal(t, map[string]string{testHost: CircuitStateOpen, "other.com": CircuitStateClosed}, breaker.States())
+	assert.EqualError(t, breaker.HealthCheck(context.Background()), "circuits are not closed: test.com (open)")
+}
+
+func TestCircuitBreaker_Options(t *testing.T) {
+	breaker := NewCircuitBreaker(
+		WithHalfOpenRequests(0),
+		WithFailureCondition(func(resp *http.Response, err error) bool { return resp.StatusCode >= http.StatusBadRequest }),
+		WithBreakerKey(func(req *http.Request) string { return req.URL.Path }),
+	)
+	assert.Equal(t, 1, breaker.config.HalfOpenRequests)
+	assert.True(t, breaker.config.IsFailure(&http.Response{StatusCode: http.StatusNotFound}, nil))
+	req, _ := http.NewRequest(http.MethodGet, testBaseURL+"/users", nil)
+	assert.Equal(t, "/users", breaker.config.Key(req))
+
+	assert.True(t, isFailure(nil, fmt.Errorf("connection refused")))
+	assert.True(t, isFailure(&http.Response{StatusCode: http.StatusInternalServerError}, nil))
+	assert.False(t, isFailure(&http.Response{StatusCode: http.StatusTooManyRequests}, nil))
+}
@@ -0,0 +1,232 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/trace"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	RetryAfterHeaderKey = "Retry-After"
+
+	DefaultTimeout          = 30 * time.Second
+	DefaultRetryCount       = 3
+	DefaultRetryWaitTime    = 100 * time.Millisecond
+	DefaultRetryMaxWaitTime = 2 * time.Second
+)
+
+var (
+	// DefaultRetryStatuses are the statuses of the responses that are retried by default.
+	DefaultRetryStatuses = []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+
+	// idempotentMethods are the methods of the requests that can be retried.
+	idempotentMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodPut,
+		http.MethodDelete,
+		http.MethodTrace,
+	}
+)
+
+type (
+	// Config represents the configuration of a client.
+	Config struct {
+		BaseURL          string
+		Timeout          time.Duration
+		RetryCount       int
+		RetryWaitTime    time.Duration
+		RetryMaxWaitTime time.Duration
+		RetryStatuses    []int
+		Username         string
+		Password         string
+		Token            string
+	}
+
+	// Option is an option that can be used to configure a client.
+	Option func(config *Config)
+)
+
+// WithBaseURL is an option that defines the URL the relative URLs of the requests are resolved against.
+func WithBaseURL(baseURL string) Option {
+	return func(config *Config) {
+		config.BaseURL = baseURL
+	}
+}
+
+// WithTimeout is an option that defines the time limit of a single attempt of a request,
+// including reading the response body. Default timeout is 30 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *Config) {
+		config.Timeout = timeout
+	}
+}
+
+// WithRetry is an option that defines the number of retries and the minimum and maximum wait time between attempts.
+// The wait time grows exponentially with jitter. Retries are disabled if the count is 0.
+// Default is 3 retries with a wait time between 100 milliseconds and 2 seconds.
+func WithRetry(count int, waitTime, maxWaitTime time.Duration) Option {
+	return func(config *Config) {
+		config.RetryCount = count
+		config.RetryWaitTime = waitTime
+		config.RetryMaxWaitTime = maxWaitTime
+	}
+}
+
+// WithRetryStatuses is an option that defines the statuses of the responses that are retried.
+// Default statuses are DefaultRetryStatuses.
+func WithRetryStatuses(statuses ...int) Option {
+	return func(config *Config) {
+		config.RetryStatuses = statuses
+	}
+}
+
+// WithBasicAuth is an option that sets the basic authentication credentials on all requests.
+func WithBasicAuth(username, password string) Option {
+	return func(config *Config) {
+		config.Username = username
+		config.Password = password
+	}
+}
+
+// WithBearerToken is an option that sets the bearer token on all requests.
+func WithBearerToken(token string) Option {
+	return func(config *Config) {
+		config.Token = token
+	}
+}
+
+// New returns a resty client that is configured with the options.
+// Requests with an idempotent method are retried if the request failed without a response or if the status of the
+// response is one of the retry statuses. The Retry-After header of the response is respected within the maximum
+// wait time. The Trace-Id header is set to the trace id of the request context if it is not set, see
+// trace.NewContext and resty.Request.SetContext.
+// Error response bodies are decoded into errors.Errors, see ResponseError.
+// The client can be instrumented further, for example with logger.UseRestyLogger or httputil.TraceResty,
+// and guarded with a CircuitBreaker, see CircuitBreaker.Wrap.
+func New(opts ...Option) *resty.Client {
+	config := &Config{
+		Timeout:          DefaultTimeout,
+		RetryCount:       DefaultRetryCount,
+		RetryWaitTime:    DefaultRetryWaitTime,
+		RetryMaxWaitTime: DefaultRetryMaxWaitTime,
+		RetryStatuses:    DefaultRetryStatuses,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	client := resty.New().
+		SetTimeout(config.Timeout).
+		SetError(&errors.Errors{}).
+		SetRetryCount(config.RetryCount).
+		SetRetryWaitTime(config.RetryWaitTime).
+		SetRetryMaxWaitTime(config.RetryMaxWaitTime).
+		SetRetryAfter(retryAfter).
+		AddRetryCondition(config.retryCondition).
+		OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			if req.Header.Get(trace.HeaderKey) == "" {
+				if traceId := trace.FromContext(req.Context()); traceId != "" {
+					req.Header.Set(trace.HeaderKey, traceId)
+				}
+			}
+			return nil
+		})
+	if config.BaseURL != "" {
+		client.SetBaseURL(config.BaseURL)
+	}
+	if config.Username != "" {
+		client.SetBasicAuth(config.Username, config.Password)
+	}
+	if config.Token != "" {
+		client.SetAuthToken(config.Token)
+	}
+	return client
+}
+
+// retryCondition checks if the attempt of a request must be retried.
+func (config *Config) retryCondition(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil || !slices.Contains(idempotentMethods, resp.Request.Method) {
+		return false
+	}
+	if resp.RawResponse == nil {
+		var openErr *CircuitOpenError
+		return err != nil && !stderrors.As(err, &openErr)
+	}
+	return slices.Contains(config.RetryStatuses, resp.StatusCode())
+}
+
+// retryAfter returns the wait time of the Retry-After header of the response in seconds.
+// If the header is not set, 0 is returned and the exponential backoff is used.
+func retryAfter(_ *resty.Client, resp *resty.Response) (time.Duration, error) {
+	seconds, err := strconv.Atoi(resp.Header().Get(RetryAfterHeaderKey))
+	if err != nil || seconds <= 0 {
+		return 0, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// ResponseError returns the error of a request that was executed with a client, or nil if the request succeeded.
+// Requests that were rejected by a CircuitBreaker return a ErrCodeCircuitOpen error with the status
+// 503 Service Unavailable.
+// Requests that failed without a response, or whose response without an error status could not be read completely,
+// return a ErrCodeUpstreamUnavailable error with the status 504 Gateway Timeout if the request timed out and
+// 502 Bad Gateway otherwise.
+// For responses with a 4xx or 5xx status, the first error of the errors.Errors body is returned.
+// If the body is not an errors.Errors body, a ErrCodeUpstreamError error with the status of the response is returned.
+// The status of the response is used if the decoded error does not have a status.
+func ResponseError(resp *resty.Response, err error) *errors.Error {
+	if err != nil && (resp == nil || resp.RawResponse == nil || !resp.IsError()) {
+		var openErr *CircuitOpenError
+		if stderrors.As(err, &openErr) {
+			return errors.Newf(errors.ErrCodeCircuitOpen, http.StatusServiceUnavailable,
+				errors.ErrMsg[errors.ErrCodeCircuitOpen], openErr.Key)
+		}
+		status := http.StatusBadGateway
+		var netErr net.Error
+		if stderrors.Is(err, context.DeadlineExceeded) || stderrors.As(err, &netErr) && netErr.Timeout() {
+			status = http.StatusGatewayTimeout
+		}
+		// the url.Error is unwrapped, because the URL might contain credentials
+		var urlErr *url.Error
+		if stderrors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return errors.Newf(errors.ErrCodeUpstreamUnavailable, status, errors.ErrMsg[errors.ErrCodeUpstreamUnavailable], err)
+	}
+	if resp == nil || !resp.IsError() {
+		return nil
+	}
+
+	errs, ok := resp.Error().(*errors.Errors)
+	if !ok || len(errs.Errors) == 0 {
+		// the body is not decoded by resty if the response does not have a JSON content type
+		errs = new(errors.Errors)
+		_ = json.Unmarshal(resp.Body(), errs)
+	}
+	if len(errs.Errors) == 0 || errs.Errors[0].Code == "" {
+		return errors.Newf(errors.ErrCodeUpstreamError, resp.StatusCode(),
+			errors.ErrMsg[errors.ErrCodeUpstreamError], resp.StatusCode())
+	}
+	e := errs.Errors[0]
+	if e.Status == 0 {
+		e.Status = resp.StatusCode()
+	}
+	return &e
+}
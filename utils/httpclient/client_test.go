@@ -0,0 +1,233 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/httputil"
+	"github.com/atselvan/go-utils/utils/trace"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testBaseURL = "https://test.com"
+	testErrors  = `{"errors":[{"code":"NOT_FOUND","status":404,"message":"user was not found","traceId":"trace"}]}`
+)
+
+func newMockClient(opts ...Option) *resty.Client {
+	client := New(append([]Option{WithBaseURL(testBaseURL), WithRetry(2, time.Millisecond, 5*time.Millisecond)}, opts...)...)
+	httpmock.ActivateNonDefault(client.GetClient())
+	return client
+}
+
+func TestNew(t *testing.T) {
+	client := New()
+	assert.Equal(t, DefaultTimeout, client.GetClient().Timeout)
+	assert.Equal(t, DefaultRetryCount, client.RetryCount)
+	assert.Equal(t, DefaultRetryWaitTime, client.RetryWaitTime)
+	assert.Equal(t, DefaultRetryMaxWaitTime, client.RetryMaxWaitTime)
+	assert.Empty(t, client.BaseURL)
+
+	client = New(WithBaseURL(testBaseURL), WithTimeout(time.Second), WithRetry(0, 0, 0))
+	assert.Equal(t, testBaseURL, client.BaseURL)
+	assert.Equal(t, time.Second, client.GetClient().Timeout)
+	assert.Equal(t, 0, client.RetryCount)
+}
+
+func TestNew_Retry(t *testing.T) {
+	client := newMockClient()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/unavailable",
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodPost, testBaseURL+"/unavailable",
+		httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/error",
+		httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	httpmock.RegisterResponder(http.MethodPut, testBaseURL+"/failure",
+		httpmock.NewErrorResponder(fmt.Errorf("connection refused")))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/recovered",
+		httpmock.NewStringResponder(http.StatusBadGateway, "").Then(httpmock.NewStringResponder(http.StatusOK, "OK")))
+
+	t.Run("idempotent method", func(t *testing.T) {
+		resp, err := client.R().Get("/unavailable")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode())
+		assert.Equal(t, 3, resp.Request.Attempt)
+	})
+
+	t.Run("non-idempotent method", func(t *testing.T) {
+		resp, _ := client.R().Post("/unavailable")
+		assert.Equal(t, 1, resp.Request.Attempt)
+	})
+
+	t.Run("status that is not retried", func(t *testing.T) {
+		resp, _ := client.R().Get("/error")
+		assert.Equal(t, 1, resp.Request.Attempt)
+	})
+
+	t.Run("failure without a response", func(t *testing.T) {
+		resp, err := client.R().Put("/failure")
+		assert.Error(t, err)
+		assert.Equal(t, 3, resp.Request.Attempt)
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		resp, err := client.R().Get("/recovered")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Equal(t, 2, resp.Request.Attempt)
+	})
+
+	t.Run("retry statuses", func(t *testing.T) {
+		client := newMockClient(WithRetryStatuses(http.StatusInternalServerError))
+		resp, _ := client.R().Get("/error")
+		assert.Equal(t, 3, resp.Request.Attempt)
+		resp, _ = client.R().Get("/unavailable")
+		assert.Equal(t, 1, resp.Request.Attempt)
+	})
+}
+
+func TestNew_Headers(t *testing.T) {
+	var headers http.Header
+	responder := func(req *http.Request) (*http.Response, error) {
+		headers = req.Header
+		return httpmock.NewStringResponse(http.StatusOK, "OK"), nil
+	}
+
+	t.Run("trace id", func(t *testing.T) {
+		client := newMockClient()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/users", responder)
+
+		_, _ = client.R().SetContext(trace.NewContext(context.Background(), "trace")).Get("/users")
+		assert.Equal(t, "trace", headers.Get(trace.HeaderKey))
+
+		_, _ = client.R().SetContext(trace.NewContext(context.Background(), "trace")).
+			SetHeader(trace.HeaderKey, "custom").Get("/users")
+		assert.Equal(t, "custom", headers.Get(trace.HeaderKey))
+
+		_, _ = client.R().Get("/users")
+		assert.Empty(t, headers.Get(trace.HeaderKey))
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		client := newMockClient(WithBasicAuth("user", "password"))
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/users", responder)
+
+		_, _ = client.R().Get("/users")
+		assert.Equal(t, "Basic dXNlcjpwYXNzd29yZA==", headers.Get(httputil.AuthorizationHeaderKey))
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		client := newMockClient(WithBearerToken("token"))
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/users", responder)
+
+		_, _ = client.R().Get("/users")
+		assert.Equal(t, "Bearer token", headers.Get(httputil.AuthorizationHeaderKey))
+	})
+}
+
+func TestResponseError(t *testing.T) {
+	client := newMockClient(WithRetry(0, 0, 0))
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/ok",
+		httputil.NewStringToJsonResponder(http.StatusOK, `{"id":1}`))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/errors",
+		httputil.NewStringToJsonResponder(http.StatusNotFound, testErrors))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/text",
+		httpmock.NewStringResponder(http.StatusNotFound, testErrors))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/status",
+		httputil.NewStringToJsonResponder(http.StatusConflict, `{"errors":[{"code":"CONFLICT","message":"conflict"}]}`))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/html",
+		httpmock.NewStringResponder(http.StatusBadGateway, "<html>Bad Gateway</html>"))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/failure",
+		httpmock.NewErrorResponder(fmt.Errorf("connection refused")))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/timeout",
+		httpmock.NewErrorResponder(context.DeadlineExceeded))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/truncated", newBrokenBodyResponder(fmt.Errorf("connection reset")))
+	httpmock.RegisterResponder(http.MethodGet, testBaseURL+"/slow-body", newBrokenBodyResponder(context.DeadlineExceeded))
+
+	t.Run("success", func(t *testing.T) {
+		assert.Nil(t, ResponseError(client.R().Get("/ok")))
+	})
+
+	t.Run("errors body", func(t *testing.T) {
+		expected := &errors.Error{Code: errors.ErrCodeNotFound, Status: http.StatusNotFound, Message: "user was not found", TraceId: "trace"}
+		assert.Equal(t, expected, ResponseError(client.R().Get("/errors")))
+		assert.Equal(t, expected, ResponseError(client.R().Get("/text")))
+	})
+
+	t.Run("status of the response", func(t *testing.T) {
+		err := ResponseError(client.R().Get("/status"))
+		assert.Equal(t, errors.ErrCodeConflict, err.Code)
+		assert.Equal(t, http.StatusConflict, err.Status)
+	})
+
+	t.Run("unknown body", func(t *testing.T) {
+		err := ResponseError(client.R().Get("/html"))
+		assert.Equal(t, errors.ErrCodeUpstreamError, err.Code)
+		assert.Equal(t, http.StatusBadGateway, err.Status)
+		assert.Equal(t, "Upstream service responded with status 502", err.Message)
+	})
+
+	t.Run("failure without a response", func(t *testing.T) {
+		err := ResponseError(client.R().Get("/failure?token=secret"))
+		assert.Equal(t, errors.ErrCodeUpstreamUnavailable, err.Code)
+		assert.Equal(t, http.StatusBadGateway, err.Status)
+		assert.Equal(t, "Unable to call the upstream service : connection refused", err.Message)
+
+		err = ResponseError(client.R().Get("/timeout"))
+		assert.Equal(t, errors.ErrCodeUpstreamUnavailable, err.Code)
+		assert.Equal(t, http.StatusGatewayTimeout, err.Status)
+	})
+
+	t.Run("body that cannot be read", func(t *testing.T) {
+		resp, rErr := client.R().Get("/truncated")
+		assert.Error(t, rErr)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		err := ResponseError(resp, rErr)
+		assert.Equal(t, errors.ErrCodeUpstreamUnavailable, err.Code)
+		assert.Equal(t, http.StatusBadGateway, err.Status)
+		assert.Equal(t, "Unable to call the upstream service : connection reset", err.Message)
+
+		err = ResponseError(client.R().Get("/slow-body"))
+		assert.Equal(t, errors.ErrCodeUpstreamUnavailable, err.Code)
+		assert.Equal(t, http.StatusGatewayTimeout, err.Status)
+	})
+}
+
+// newBrokenBodyResponder returns a responder with a 200 OK response whose body fails with the error when it is read.
+func newBrokenBodyResponder(err error) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "")
+		resp.Body = io.NopCloser(iotest.ErrReader(err))
+		return resp, nil
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for header, expected := range map[string]time.Duration{
+		"":     0,
+		"2":    2 * time.Second,
+		"0":    0,
+		"soon": 0,
+	} {
+		resp := &resty.Response{RawResponse: &http.Response{Header: http.Header{}}}
+		resp.RawResponse.Header.Set(RetryAfterHeaderKey, header)
+		wait, err := retryAfter(nil, resp)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, wait, header)
+	}
+}
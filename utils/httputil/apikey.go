@@ -0,0 +1,254 @@
+package httputil
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/fileutil"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ApiKeyHeaderKey  = "X-API-Key"
+	ApiKeyContextKey = "apiKey"
+
+	apiKeyMissingMsg       = "API key is required"
+	apiKeyInvalidMsg       = "API key is not valid"
+	apiKeyExpiredMsg       = "API key '%s' of consumer '%s' is expired"
+	apiKeyFileReloadErrMsg = "Unable to reload API key file '%s', using the previously loaded keys: %s"
+)
+
+type (
+	// ApiKey represents an API key. The key itself is never stored, only the SHA-256 hash of the key.
+	// A consumer can have multiple active keys, which allows keys to be rotated without downtime.
+	ApiKey struct {
+		Id         string    `json:"id"`
+		Hash       string    `json:"hash"`
+		ConsumerId string    `json:"consumerId"`
+		Roles      []string  `json:"roles,omitempty"`
+		Scopes     []string  `json:"scopes,omitempty"`
+		ExpiresAt  time.Time `json:"expiresAt"`
+	}
+
+	// ApiKeyStore looks up API keys by the SHA-256 hash of the key.
+	// Lookup returns false if the key does not exist.
+	ApiKeyStore interface {
+		Lookup(ctx context.Context, hash string) (*ApiKey, bool)
+	}
+
+	// MemoryApiKeyStore is an ApiKeyStore that keeps the keys in memory.
+	MemoryApiKeyStore struct {
+		mu   sync.RWMutex
+		keys map[string]*ApiKey
+	}
+
+	// ApiKeyFile is an ApiKeyStore that reads the keys from a JSON file with a list of ApiKey.
+	// The file is reloaded when it changes.
+	ApiKeyFile struct {
+		path string
+
+		mu      sync.RWMutex
+		keys    map[string]*ApiKey
+		modTime time.Time
+		size    int64
+	}
+
+	// ApiKeyConfig represents the configuration of the ApiKeyAuth middleware.
+	ApiKeyConfig struct {
+		Header     string
+		QueryParam string
+	}
+
+	// ApiKeyOption is an option that can be used to configure the ApiKeyAuth middleware.
+	ApiKeyOption func(config *ApiKeyConfig)
+)
+
+// HashApiKey returns the hex encoded SHA-256 hash of the API key.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Expired checks if the key is expired. A key without an expiry date never expires.
+func (k *ApiKey) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// NewMemoryApiKeyStore returns a MemoryApiKeyStore with the keys.
+// The Hash of the keys must be set, see HashApiKey.
+func NewMemoryApiKeyStore(keys ...ApiKey) *MemoryApiKeyStore {
+	s := &MemoryApiKeyStore{keys: make(map[string]*ApiKey)}
+	for _, k := range keys {
+		s.Put(k)
+	}
+	return s
+}
+
+// Add hashes the key and stores it with the properties of apiKey.
+func (s *MemoryApiKeyStore) Add(key string, apiKey ApiKey) {
+	apiKey.Hash = HashApiKey(key)
+	s.Put(apiKey)
+}
+
+// Put stores the apiKey by its Hash.
+func (s *MemoryApiKeyStore) Put(apiKey ApiKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys[strings.ToLower(apiKey.Hash)] = &apiKey
+}
+
+// Revoke removes the key with the id.
+func (s *MemoryApiKeyStore) Revoke(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, k := range s.keys {
+		if k.Id == id {
+			delete(s.keys, hash)
+		}
+	}
+}
+
+// Lookup returns the key with the hash.
+func (s *MemoryApiKeyStore) Lookup(_ context.Context, hash string) (*ApiKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[hash]
+	return k, ok
+}
+
+// NewApiKeyFile returns an ApiKeyStore that reads the keys from the JSON file.
+// The method returns an error if the file cannot be read or parsed.
+func NewApiKeyFile(filePath string) (*ApiKeyFile, *errors.Error) {
+	f := &ApiKeyFile{path: filePath}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Lookup returns the key with the hash. The file is reloaded first if it was modified since it was last loaded.
+func (f *ApiKeyFile) Lookup(_ context.Context, hash string) (*ApiKey, bool) {
+	if f.changed() {
+		if err := f.load(); err != nil {
+			logger.Errorf(apiKeyFileReloadErrMsg, f.path, err.Message)
+		}
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	k, ok := f.keys[hash]
+	return k, ok
+}
+
+// changed checks if the file was modified since it was last loaded.
+func (f *ApiKeyFile) changed() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return !info.ModTime().Equal(f.modTime) || info.Size() != f.size
+}
+
+// load reads the keys from the file.
+func (f *ApiKeyFile) load() *errors.Error {
+	info, sErr := os.Stat(f.path)
+	if sErr != nil {
+		return errors.Newf(errors.ErrCodeFileNotFound, 0, errors.ErrMsg[errors.ErrCodeFileNotFound], f.path)
+	}
+	var list []ApiKey
+	if err := fileutil.ReadJsonFile(f.path, &list); err != nil {
+		return err
+	}
+	keys := make(map[string]*ApiKey, len(list))
+	for i := range list {
+		keys[strings.ToLower(list[i].Hash)] = &list[i]
+	}
+	f.mu.Lock()
+	f.keys = keys
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.mu.Unlock()
+	return nil
+}
+
+// WithApiKeyHeader is an ApiKeyAuth option that defines the header the key is read from.
+// Default header is X-API-Key. An empty header disables reading the key from a header.
+func WithApiKeyHeader(header string) ApiKeyOption {
+	return func(config *ApiKeyConfig) {
+		config.Header = header
+	}
+}
+
+// WithApiKeyQueryParam is an ApiKeyAuth option that defines the query parameter the key is read from
+// if it is not set in the header. Reading the key from a query parameter is disabled by default.
+func WithApiKeyQueryParam(param string) ApiKeyOption {
+	return func(config *ApiKeyConfig) {
+		config.QueryParam = param
+	}
+}
+
+// ApiKeyAuth is a gin middleware that authenticates requests with an API key.
+// The key is looked up in the store by its SHA-256 hash. The ApiKey is stored in the gin context
+// and can be retrieved using GetApiKey. The consumer id and scopes of the key are set as the Principal.
+// The method returns an unauthorized error if the key is missing, unknown or expired.
+func ApiKeyAuth(store ApiKeyStore, opts ...ApiKeyOption) gin.HandlerFunc {
+	config := &ApiKeyConfig{Header: ApiKeyHeaderKey}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(ctx *gin.Context) {
+		key := config.getKey(ctx)
+		if key == "" {
+			AbortWithError(ctx, errors.UnauthorizedError(apiKeyMissingMsg))
+			return
+		}
+
+		apiKey, ok := store.Lookup(ctx.Request.Context(), HashApiKey(key))
+		if !ok || apiKey == nil {
+			AbortWithError(ctx, errors.UnauthorizedError(apiKeyInvalidMsg))
+			return
+		}
+		if apiKey.Expired(time.Now()) {
+			logger.Infof(apiKeyExpiredMsg, apiKey.Id, apiKey.ConsumerId)
+			AbortWithError(ctx, errors.UnauthorizedError(apiKeyInvalidMsg))
+			return
+		}
+
+		ctx.Set(ApiKeyContextKey, apiKey)
+		ctx.Set(AuthUserKey, apiKey.ConsumerId)
+		SetPrincipal(ctx, &Principal{Id: apiKey.ConsumerId, Roles: apiKey.Roles, Scopes: apiKey.Scopes})
+		logger.Info(authenticationSuccessMsg)
+		ctx.Next()
+	}
+}
+
+// getKey gets the key from the header or the query parameter.
+func (config *ApiKeyConfig) getKey(ctx *gin.Context) string {
+	if config.Header != "" {
+		if key := strings.TrimSpace(ctx.GetHeader(config.Header)); key != "" {
+			return key
+		}
+	}
+	if config.QueryParam != "" {
+		return strings.TrimSpace(ctx.Query(config.QueryParam))
+	}
+	return ""
+}
+
+// GetApiKey returns the ApiKey that was stored in the gin context by the ApiKeyAuth middleware.
+func GetApiKey(ctx *gin.Context) (*ApiKey, bool) {
+	v, ok := ctx.Get(ApiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	apiKey, ok := v.(*ApiKey)
+	return apiKey, ok
+}
@@ -0,0 +1,144 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	apiKeyMissingResponse = `{"errors":[{"code":"UNAUTHORIZED","status":401,"message":"API key is required","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	apiKeyInvalidResponse = `{"errors":[{"code":"UNAUTHORIZED","status":401,"message":"API key is not valid","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+)
+
+func getMockApiKeyStore() *MemoryApiKeyStore {
+	store := NewMemoryApiKeyStore()
+	store.Add("key-1", ApiKey{Id: "1", ConsumerId: "service-a", Scopes: []string{"read"}})
+	store.Add("key-2", ApiKey{Id: "2", ConsumerId: "service-a", Scopes: []string{"read"}})
+	store.Add("expired", ApiKey{Id: "3", ConsumerId: "service-b", ExpiresAt: time.Now().Add(-time.Minute)})
+	return store
+}
+
+func TestApiKey_Expired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, (&ApiKey{}).Expired(now))
+	assert.False(t, (&ApiKey{ExpiresAt: now.Add(time.Minute)}).Expired(now))
+	assert.True(t, (&ApiKey{ExpiresAt: now}).Expired(now))
+}
+
+func TestMemoryApiKeyStore(t *testing.T) {
+	store := getMockApiKeyStore()
+
+	k, ok := store.Lookup(context.Background(), HashApiKey("key-1"))
+	assert.True(t, ok)
+	assert.Equal(t, "service-a", k.ConsumerId)
+
+	store.Revoke("1")
+	_, ok = store.Lookup(context.Background(), HashApiKey("key-1"))
+	assert.False(t, ok)
+	_, ok = store.Lookup(context.Background(), HashApiKey("key-2"))
+	assert.True(t, ok)
+}
+
+func TestApiKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	writeKeys := func(keys ...ApiKey) {
+		data, err := json.Marshal(keys)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(path, data, 0600))
+	}
+	writeKeys(ApiKey{Id: "1", Hash: HashApiKey("key-1"), ConsumerId: "service-a"})
+
+	store, cErr := NewApiKeyFile(path)
+	assert.Nil(t, cErr)
+	_, ok := store.Lookup(context.Background(), HashApiKey("key-1"))
+	assert.True(t, ok)
+	_, ok = store.Lookup(context.Background(), HashApiKey("key-2"))
+	assert.False(t, ok)
+
+	t.Run("reload on change", func(t *testing.T) {
+		writeKeys(
+			ApiKey{Id: "1", Hash: HashApiKey("key-1"), ConsumerId: "service-a"},
+			ApiKey{Id: "2", Hash: HashApiKey("key-2"), ConsumerId: "service-a"},
+		)
+		modTime := time.Now().Add(time.Second)
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		_, ok := store.Lookup(context.Background(), HashApiKey("key-2"))
+		assert.True(t, ok)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "invalid.json")
+		assert.NoError(t, os.WriteFile(invalid, []byte("{"), 0600))
+		store, cErr := NewApiKeyFile(invalid)
+		assert.Nil(t, store)
+		assert.NotNil(t, cErr)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, cErr := NewApiKeyFile(filepath.Join(t.TempDir(), "missing"))
+		assert.Nil(t, store)
+		assert.NotNil(t, cErr)
+	})
+}
+
+func TestApiKeyAuth(t *testing.T) {
+	router := setupMockRouter(setMockTraceId, ApiKeyAuth(getMockApiKeyStore(), WithApiKeyQueryParam("api_key")))
+
+	tests := []struct {
+		name       string
+		header     string
+		query      string
+		statusCode int
+		response   string
+	}{
+		{name: "header", header: "key-1", statusCode: http.StatusOK, response: "OK"},
+		{name: "rotated key", header: "key-2", statusCode: http.StatusOK, response: "OK"},
+		{name: "query param", query: "?api_key=key-1", statusCode: http.StatusOK, response: "OK"},
+		{name: "unknown key", header: "unknown", statusCode: http.StatusUnauthorized, response: apiKeyInvalidResponse},
+		{name: "expired key", header: "expired", statusCode: http.StatusUnauthorized, response: apiKeyInvalidResponse},
+		{name: "missing key", statusCode: http.StatusUnauthorized, response: apiKeyMissingResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/login"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set(ApiKeyHeaderKey, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestApiKeyAuth_Context(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(ApiKeyAuth(getMockApiKeyStore()), RequireScopes("read"))
+	r.GET("/login", func(ctx *gin.Context) {
+		apiKey, ok := GetApiKey(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "1", apiKey.Id)
+		ctx.String(http.StatusOK, ctx.GetString(AuthUserKey))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/login?api_key=key-1", nil)
+	req.Header.Set(ApiKeyHeaderKey, "key-1")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "service-a", w.Body.String())
+}
@@ -0,0 +1,195 @@
+package httputil
+
+import (
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	PrincipalContextKey = "principal"
+
+	MatchAny MatchMode = "any"
+	MatchAll MatchMode = "all"
+
+	authenticationRequiredMsg = "Authentication is required"
+)
+
+type (
+	// Principal represents the authenticated caller of a request.
+	// The principal is stored in the gin context by the authentication middlewares.
+	Principal struct {
+		Id     string
+		Roles  []string
+		Scopes []string
+	}
+
+	// MatchMode defines if any or all of the roles or scopes of a Policy are required.
+	MatchMode string
+
+	// Policy defines the roles and scopes that are required to access a route.
+	// If both roles and scopes are defined, the principal must satisfy both.
+	// The default MatchMode is MatchAny.
+	Policy struct {
+		Roles  []string
+		Scopes []string
+		Match  MatchMode
+	}
+)
+
+// SetPrincipal stores the principal in the gin context.
+func SetPrincipal(ctx *gin.Context, principal *Principal) {
+	ctx.Set(PrincipalContextKey, principal)
+}
+
+// GetPrincipal returns the principal that was stored in the gin context by an authentication middleware.
+func GetPrincipal(ctx *gin.Context) (*Principal, bool) {
+	v, ok := ctx.Get(PrincipalContextKey)
+	if !ok {
+		return nil, false
+	}
+	principal, ok := v.(*Principal)
+	return principal, ok
+}
+
+// HasRoles checks if the principal has any or all of the roles.
+func (p *Principal) HasRoles(match MatchMode, roles ...string) bool {
+	return matches(p.Roles, roles, match)
+}
+
+// HasScopes checks if the principal has any or all of the scopes.
+func (p *Principal) HasScopes(match MatchMode, scopes ...string) bool {
+	return matches(p.Scopes, scopes, match)
+}
+
+// Allows checks if the principal satisfies the policy.
+func (policy Policy) Allows(p *Principal) bool {
+	match := policy.Match
+	if match == "" {
+		match = MatchAny
+	}
+	if len(policy.Roles) > 0 && !p.HasRoles(match, policy.Roles...) {
+		return false
+	}
+	if len(policy.Scopes) > 0 && !p.HasScopes(match, policy.Scopes...) {
+		return false
+	}
+	return true
+}
+
+// matches checks if any or all the required values are present.
+func matches(values, required []string, match MatchMode) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, r := range required {
+		found := slice.EntryExists(values, r)
+		if found && match != MatchAll {
+			return true
+		}
+		if !found && match == MatchAll {
+			return false
+		}
+	}
+	return match == MatchAll
+}
+
+// Authorize is a gin middleware that checks if the principal that was stored in the gin context
+// by an authentication middleware satisfies the policy.
+// The method returns an unauthorized error if there is no principal and
+// a forbidden error if the principal does not satisfy the policy.
+func Authorize(policy Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if authorize(ctx, policy) {
+			ctx.Next()
+		}
+	}
+}
+
+// AuthorizeRoutes is a gin middleware that checks the policy of the route of the request.
+// The policies are mapped by route template (gin FullPath), optionally prefixed by the method, for example
+// "/users/:id" or "GET /users/:id". A policy with a method takes precedence.
+// Requests on routes without a policy are allowed.
+func AuthorizeRoutes(policies map[string]Policy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		policy, ok := policies[ctx.Request.Method+" "+ctx.FullPath()]
+		if !ok {
+			policy, ok = policies[ctx.FullPath()]
+		}
+		if !ok || authorize(ctx, policy) {
+			ctx.Next()
+		}
+	}
+}
+
+// RequireRoles is a gin middleware that requires the principal to have any of the roles.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return Authorize(Policy{Roles: roles, Match: MatchAny})
+}
+
+// RequireAllRoles is a gin middleware that requires the principal to have all the roles.
+func RequireAllRoles(roles ...string) gin.HandlerFunc {
+	return Authorize(Policy{Roles: roles, Match: MatchAll})
+}
+
+// RequireScopes is a gin middleware that requires the principal to have any of the scopes.
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return Authorize(Policy{Scopes: scopes, Match: MatchAny})
+}
+
+// RequireAllScopes is a gin middleware that requires the principal to have all the scopes.
+func RequireAllScopes(scopes ...string) gin.HandlerFunc {
+	return Authorize(Policy{Scopes: scopes, Match: MatchAll})
+}
+
+// authorize checks the policy and aborts the request if the check fails.
+func authorize(ctx *gin.Context, policy Policy) bool {
+	principal, ok := GetPrincipal(ctx)
+	if !ok || principal == nil {
+		AbortWithError(ctx, errors.UnauthorizedError(authenticationRequiredMsg))
+		return false
+	}
+	if !policy.Allows(principal) {
+		AbortWithError(ctx, errors.ForbiddenError(errors.ErrMsg[errors.ErrCodeInsufficientAccess]))
+		return false
+	}
+	return true
+}
+
+// principalFromClaims returns the principal that is represented by the JWT claims.
+// Roles are read from the roles claim and scopes from the scope (space separated) or scp claim.
+func principalFromClaims(claims jwt.MapClaims) *Principal {
+	sub, _ := claims.GetSubject()
+	principal := &Principal{
+		Id:    sub,
+		Roles: claimStrings(claims["roles"]),
+	}
+	if scope, ok := claims["scope"].(string); ok {
+		principal.Scopes = strings.Fields(scope)
+	} else {
+		principal.Scopes = claimStrings(claims["scp"])
+	}
+	return principal
+}
+
+// claimStrings converts a claim that is either a string or a list of strings to a slice of strings.
+func claimStrings(claim any) []string {
+	switch v := claim.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	forbiddenResponse       = `{"errors":[{"code":"INSUFFICIENT_ACCESS","status":403,"message":"Insufficient access","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	authRequiredResponse    = `{"errors":[{"code":"UNAUTHORIZED","status":401,"message":"Authentication is required","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	mockPrincipalHeaderName = "X-Mock-Principal"
+)
+
+func setMockPrincipal(principal *Principal) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader(mockPrincipalHeaderName) != "" {
+			SetPrincipal(ctx, principal)
+		}
+		ctx.Next()
+	}
+}
+
+func TestPolicy_Allows(t *testing.T) {
+	principal := &Principal{Id: "admin", Roles: []string{"admin", "user"}, Scopes: []string{"read"}}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   bool
+	}{
+		{name: "empty policy", policy: Policy{}, want: true},
+		{name: "any role", policy: Policy{Roles: []string{"auditor", "user"}}, want: true},
+		{name: "any role missing", policy: Policy{Roles: []string{"auditor"}}, want: false},
+		{name: "all roles", policy: Policy{Roles: []string{"admin", "user"}, Match: MatchAll}, want: true},
+		{name: "all roles missing", policy: Policy{Roles: []string{"admin", "auditor"}, Match: MatchAll}, want: false},
+		{name: "roles and scopes", policy: Policy{Roles: []string{"admin"}, Scopes: []string{"read"}}, want: true},
+		{name: "roles and missing scopes", policy: Policy{Roles: []string{"admin"}, Scopes: []string{"write"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Allows(principal))
+		})
+	}
+}
+
+func TestRequireRoles(t *testing.T) {
+	principal := &Principal{Id: "admin", Roles: []string{"admin"}, Scopes: []string{"read", "write"}}
+
+	tests := []struct {
+		name          string
+		middleware    gin.HandlerFunc
+		withPrincipal bool
+		statusCode    int
+		response      string
+	}{
+		{name: "roles", middleware: RequireRoles("user", "admin"), withPrincipal: true, statusCode: http.StatusOK, response: "OK"},
+		{name: "all roles", middleware: RequireAllRoles("user", "admin"), withPrincipal: true, statusCode: http.StatusForbidden, response: forbiddenResponse},
+		{name: "scopes", middleware: RequireScopes("delete"), withPrincipal: true, statusCode: http.StatusForbidden, response: forbiddenResponse},
+		{name: "all scopes", middleware: RequireAllScopes("read", "write"), withPrincipal: true, statusCode: http.StatusOK, response: "OK"},
+		{name: "no principal", middleware: RequireRoles("admin"), statusCode: http.StatusUnauthorized, response: authRequiredResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupMockRouter(setMockTraceId, setMockPrincipal(principal), tt.middleware)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+			if tt.withPrincipal {
+				req.Header.Set(mockPrincipalHeaderName, "true")
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestAuthorizeRoutes(t *testing.T) {
+	principal := &Principal{Id: "user", Roles: []string{"user"}}
+	router := setupMockRouter(setMockTraceId, setMockPrincipal(principal), AuthorizeRoutes(map[string]Policy{
+		"/users/:id":        {Roles: []string{"user", "admin"}},
+		"DELETE /users/:id": {Roles: []string{"admin"}},
+	}))
+	handler := func(ctx *gin.Context) { ctx.String(http.StatusOK, "OK") }
+	router.GET("/users/:id", handler)
+	router.DELETE("/users/:id", handler)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{name: "route policy", method: http.MethodGet, path: "/users/1", statusCode: http.StatusOK},
+		{name: "method policy", method: http.MethodDelete, path: "/users/1", statusCode: http.StatusForbidden},
+		{name: "no policy", method: http.MethodGet, path: "/login", statusCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(mockPrincipalHeaderName, "true")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestPrincipalFromAuthMiddlewares(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(BasicAuth(getMockAccount()))
+	r.GET("/login", func(ctx *gin.Context) {
+		principal, ok := GetPrincipal(ctx)
+		assert.True(t, ok)
+		ctx.String(http.StatusOK, principal.Id)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+	req.SetBasicAuth("admin", "admin")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin", w.Body.String())
+}
+
+func TestPrincipalFromClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   *Principal
+	}{
+		{
+			name:   "scope",
+			claims: jwt.MapClaims{"sub": "admin", "roles": []any{"admin", "user"}, "scope": "read write"},
+			want:   &Principal{Id: "admin", Roles: []string{"admin", "user"}, Scopes: []string{"read", "write"}},
+		},
+		{
+			name:   "scp",
+			claims: jwt.MapClaims{"sub": "user", "roles": "user", "scp": []any{"read"}},
+			want:   &Principal{Id: "user", Roles: []string{"user"}, Scopes: []string{"read"}},
+		},
+		{
+			name:   "no roles or scopes",
+			claims: jwt.MapClaims{"sub": "user"},
+			want:   &Principal{Id: "user"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, principalFromClaims(tt.claims))
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package httputil
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/structutil"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	bindTagForm = "form"
+	bindTagUri  = "uri"
+
+	unknownFieldMsg     = "unknown field"
+	invalidTypeMsg      = "must be of type %s"
+	invalidJsonMsg      = "invalid JSON at offset %d"
+	unexpectedEOFMsg    = "unexpected end of JSON"
+	requiredFieldMsg    = "is required"
+	validationFailedMsg = "failed on the '%s' validation"
+)
+
+type (
+	// BindConfig represents the configuration of the bind helpers.
+	BindConfig struct {
+		DisallowUnknownFields bool
+	}
+
+	// BindOption is an option that can be used to configure the bind helpers.
+	BindOption func(config *BindConfig)
+)
+
+// WithDisallowUnknownFields is a bind option that rejects payloads and query parameters with fields
+// that are not defined in the struct.
+func WithDisallowUnknownFields() BindOption {
+	return func(config *BindConfig) {
+		config.DisallowUnknownFields = true
+	}
+}
+
+// BindJSON decodes the JSON request body into a new T, after the defaults of the default tags are applied,
+// see structutil.SetDefaults, and validates it with the binding tags.
+// If the body cannot be decoded or is not valid, a ErrCodeInvalidPayload error is returned per field, with the
+// field named by its json tag, like the errors of OpenApiValidation. A ErrCodeRequestTooLarge error is returned if
+// the body exceeds the limit of BodyLimit. The errors can be returned with AbortWithErrors.
+func BindJSON[T any](ctx *gin.Context, opts ...BindOption) (*T, []*errors.Error) {
+	config := newBindConfig(opts)
+	out := new(T)
+	if err := structutil.SetDefaults(out); err != nil {
+		return nil, []*errors.Error{err}
+	}
+	if err := decodeJSON(ctx.Request.Body, out, config); err != nil {
+		return nil, []*errors.Error{err}
+	}
+	if errs := validateStruct(out, structutil.StructTagJson); errs != nil {
+		return nil, errs
+	}
+	return out, nil
+}
+
+// BindQuery decodes the query parameters into a new T using the form tags, after the defaults of the default tags
+// are applied, and validates it with the binding tags.
+// If the parameters cannot be decoded or are not valid, a ErrCodeInvalidPayload error is returned per parameter.
+func BindQuery[T any](ctx *gin.Context, opts ...BindOption) (*T, []*errors.Error) {
+	config := newBindConfig(opts)
+	query := ctx.Request.URL.Query()
+	out := new(T)
+	if config.DisallowUnknownFields {
+		if errs := unknownFields(reflect.TypeOf(out).Elem(), query, bindTagForm); errs != nil {
+			return nil, errs
+		}
+	}
+	return bindForm(out, query, bindTagForm)
+}
+
+// BindURI decodes the path parameters into a new T using the uri tags, after the defaults of the default tags
+// are applied, and validates it with the binding tags.
+// If the parameters cannot be decoded or are not valid, a ErrCodeInvalidPayload error is returned per parameter.
+func BindURI[T any](ctx *gin.Context) (*T, []*errors.Error) {
+	params := make(map[string][]string, len(ctx.Params))
+	for _, p := range ctx.Params {
+		params[p.Key] = []string{p.Value}
+	}
+	return bindForm(new(T), params, bindTagUri)
+}
+
+// BindJSONHandler returns a handler that binds the JSON request body with BindJSON and calls the handler
+// with the result. The request is aborted with the errors if the body cannot be bound.
+func BindJSONHandler[T any](handler func(ctx *gin.Context, body *T), opts ...BindOption) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, errs := BindJSON[T](ctx, opts...)
+		if errs != nil {
+			AbortWithErrors(ctx, errs...)
+			return
+		}
+		handler(ctx, body)
+	}
+}
+
+// BindQueryHandler returns a handler that binds the query parameters with BindQuery and calls the handler
+// with the result. The request is aborted with the errors if the parameters cannot be bound.
+func BindQueryHandler[T any](handler func(ctx *gin.Context, query *T), opts ...BindOption) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		query, errs := BindQuery[T](ctx, opts...)
+		if errs != nil {
+			AbortWithErrors(ctx, errs...)
+			return
+		}
+		handler(ctx, query)
+	}
+}
+
+// BindURIHandler returns a handler that binds the path parameters with BindURI and calls the handler
+// with the result. The request is aborted with the errors if the parameters cannot be bound.
+func BindURIHandler[T any](handler func(ctx *gin.Context, params *T)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		params, errs := BindURI[T](ctx)
+		if errs != nil {
+			AbortWithErrors(ctx, errs...)
+			return
+		}
+		handler(ctx, params)
+	}
+}
+
+// newBindConfig returns the bind configuration with the options applied.
+func newBindConfig(opts []BindOption) *BindConfig {
+	config := new(BindConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// bindForm applies the defaults, maps the values with the tag and validates the result.
+func bindForm[T any](out *T, values map[string][]string, tag string) (*T, []*errors.Error) {
+	if err := structutil.SetDefaults(out); err != nil {
+		return nil, []*errors.Error{err}
+	}
+	if err := binding.MapFormWithTag(out, values, tag); err != nil {
+		return nil, []*errors.Error{invalidPayloadError("", err.Error())}
+	}
+	if errs := validateStruct(out, tag); errs != nil {
+		return nil, errs
+	}
+	return out, nil
+}
+
+// decodeJSON decodes the JSON body into out.
+func decodeJSON(body io.Reader, out any, config *BindConfig) *errors.Error {
+	if body == nil {
+		return invalidPayloadError(requestBodyField, requestBodyRequiredMsg)
+	}
+	decoder := json.NewDecoder(body)
+	if config.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(out); err != nil {
+		return jsonDecodeError(err)
+	}
+	return nil
+}
+
+// jsonDecodeError converts an error of the JSON decoder.
+func jsonDecodeError(err error) *errors.Error {
+	var maxBytesErr *http.MaxBytesError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case stderrors.As(err, &maxBytesErr):
+		return errors.Newf(errors.ErrCodeRequestTooLarge, http.StatusRequestEntityTooLarge,
+			errors.ErrMsg[errors.ErrCodeRequestTooLarge], maxBytesErr.Limit)
+	case stderrors.Is(err, io.EOF):
+		return invalidPayloadError(requestBodyField, requestBodyRequiredMsg)
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		return invalidPayloadError(requestBodyField, unexpectedEOFMsg)
+	case stderrors.As(err, &syntaxErr):
+		return invalidPayloadError(requestBodyField, fmt.Sprintf(invalidJsonMsg, syntaxErr.Offset))
+	case stderrors.As(err, &typeErr):
+		return invalidPayloadError(typeErr.Field, fmt.Sprintf(invalidTypeMsg, typeErr.Type))
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return invalidPayloadError(field, unknownFieldMsg)
+	default:
+		return invalidPayloadError(requestBodyField, err.Error())
+	}
+}
+
+// validateStruct validates the struct with the binding tags and returns an error per validation error with the field
+// named by the first of the tags that is set.
+func validateStruct(obj any, tags ...string) []*errors.Error {
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+	var validationErrs validator.ValidationErrors
+	if !stderrors.As(err, &validationErrs) {
+		return []*errors.Error{invalidPayloadError("", err.Error())}
+	}
+	errs := make([]*errors.Error, 0, len(validationErrs))
+	t := reflect.TypeOf(obj).Elem()
+	for _, fe := range validationErrs {
+		msg := fmt.Sprintf(validationFailedMsg, fe.Tag())
+		if fe.Tag() == structutil.StructTagRequired {
+			msg = requiredFieldMsg
+		} else if fe.Param() != "" {
+			msg = fmt.Sprintf(validationFailedMsg, fe.Tag()+"="+fe.Param())
+		}
+		errs = append(errs, invalidPayloadError(fieldPath(t, fe.StructNamespace(), tags), msg))
+	}
+	return errs
+}
+
+// unknownFields returns an error per key of the values that is not a field of the struct type, sorted by key.
+func unknownFields(t reflect.Type, values map[string][]string, tag string) []*errors.Error {
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		known[fieldName(t.Field(i), tag)] = true
+	}
+	var keys []string
+	for key := range values {
+		if !known[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var errs []*errors.Error
+	for _, key := range keys {
+		errs = append(errs, invalidPayloadError(key, unknownFieldMsg))
+	}
+	return errs
+}
+
+// fieldPath converts the struct namespace of a validation error, for example User.Address.City, to the path of
+// the field names of the tags, for example address.city.
+func fieldPath(t reflect.Type, namespace string, tags []string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 0 {
+		// the first part is the name of the struct type
+		parts = parts[1:]
+	}
+	path := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, part)
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, part)
+			t = nil
+			continue
+		}
+		if index != "" {
+			index = "[" + index
+		}
+		path = append(path, fieldName(field, tags...)+index)
+		t = field.Type
+		if index != "" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// fieldName returns the name of the field in the first of the tags that is set or the name of the field
+// if none of the tags is set.
+func fieldName(field reflect.StructField, tags ...string) string {
+	for _, tag := range tags {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
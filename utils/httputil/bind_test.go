@@ -0,0 +1,208 @@
+package httputil
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	testAddress struct {
+		City string `json:"city" binding:"required"`
+	}
+
+	testUser struct {
+		Name    string       `json:"name" binding:"required,min=2"`
+		Role    string       `json:"role" default:"user" binding:"oneof=user admin"`
+		Age     int          `json:"age" binding:"gte=0"`
+		Address testAddress  `json:"address"`
+		Phones  []testPhone  `json:"phones" binding:"dive"`
+		Manager *testAddress `json:"manager,omitempty"`
+	}
+
+	testPhone struct {
+		Number string `json:"number" binding:"required,numeric"`
+	}
+
+	testUserQuery struct {
+		Limit  int    `form:"limit" default:"10" binding:"min=1,max=100"`
+		Sort   string `form:"sort"`
+		Active bool   `form:"active"`
+	}
+
+	testUserUri struct {
+		Id int `uri:"id" binding:"required,min=1"`
+	}
+)
+
+func bindRequest(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(ContentTypeHeaderKey, ApplicationJsonMIMEType)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func readError(t *testing.T, w *httptest.ResponseRecorder) errors.Error {
+	var errs errors.Errors
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errs))
+	assert.Len(t, errs.Errors, 1)
+	return errs.Errors[0]
+}
+
+func TestBindJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/users", BindJSONHandler(func(ctx *gin.Context, user *testUser) {
+		ctx.JSON(http.StatusCreated, user)
+	}))
+	r.POST("/strict/users", BindJSONHandler(func(ctx *gin.Context, user *testUser) {
+		ctx.JSON(http.StatusCreated, user)
+	}, WithDisallowUnknownFields()))
+
+	t.Run("valid", func(t *testing.T) {
+		w := bindRequest(r, http.MethodPost, "/users", `{"name":"test","address":{"city":"Amsterdam"},"unknown":true}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		user := new(testUser)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), user))
+		assert.Equal(t, "test", user.Name)
+		assert.Equal(t, "user", user.Role)
+		assert.Equal(t, "Amsterdam", user.Address.City)
+	})
+
+	t.Run("default is overwritten", func(t *testing.T) {
+		w := bindRequest(r, http.MethodPost, "/users", `{"name":"test","role":"admin","address":{"city":"Amsterdam"}}`)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"role":"admin"`)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		w := bindRequest(r, http.MethodPost, "/users",
+			`{"name":"t","role":"owner","age":-1,"phones":[{"number":"1"},{"number":"abc"}],"manager":{}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		messages := make(map[string]string)
+		for _, err := range readErrors(t, w) {
+			assert.Equal(t, errors.ErrCodeInvalidPayload, err.Code)
+			assert.Equal(t, http.StatusBadRequest, err.Status)
+			messages[err.Field] = err.Message
+		}
+		assert.Equal(t, map[string]string{
+			"name":             "Payload is not valid : failed on the 'min=2' validation",
+			"role":             "Payload is not valid : failed on the 'oneof=user admin' validation",
+			"age":              "Payload is not valid : failed on the 'gte=0' validation",
+			"address.city":     "Payload is not valid : is required",
+			"phones[1].number": "Payload is not valid : failed on the 'numeric' validation",
+			"manager.city":     "Payload is not valid : is required",
+		}, messages)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		w := bindRequest(r, http.MethodPost, "/users", `{"name":"test","address":{"city":1}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		err := readError(t, w)
+		assert.Equal(t, "address.city", err.Field)
+		assert.Equal(t, "Payload is not valid : must be of type string", err.Message)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		w := bindRequest(r, http.MethodPost, "/strict/users", `{"name":"test","unknown":true}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		err := readError(t, w)
+		assert.Equal(t, "unknown", err.Field)
+		assert.Equal(t, "Payload is not valid : "+unknownFieldMsg, err.Message)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		for body, msg := range map[string]string{
+			"":           "Payload is not valid : " + requestBodyRequiredMsg,
+			`{"name":`:   "Payload is not valid : " + unexpectedEOFMsg,
+			`{"name" 1}`: "Payload is not valid : invalid JSON at offset 9",
+		} {
+			w := bindRequest(r, http.MethodPost, "/users", body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			err := readError(t, w)
+			assert.Equal(t, errors.ErrCodeInvalidPayload, err.Code)
+			assert.Equal(t, requestBodyField, err.Field)
+			assert.Equal(t, msg, err.Message)
+		}
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"test user"}`))
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, 10)
+		_, errs := BindJSON[testUser](ctx)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errors.ErrCodeRequestTooLarge, errs[0].Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, errs[0].Status)
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		type invalid struct {
+			Limit int `default:"ten"`
+		}
+		ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+		ctx.Request, _ = http.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`))
+		_, errs := BindJSON[invalid](ctx)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errors.ErrCodeInvalidStructTag, errs[0].Code)
+	})
+}
+
+func TestBindQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/users", BindQueryHandler(func(ctx *gin.Context, query *testUserQuery) {
+		ctx.JSON(http.StatusOK, query)
+	}))
+	r.GET("/strict/users", BindQueryHandler(func(ctx *gin.Context, query *testUserQuery) {
+		ctx.JSON(http.StatusOK, query)
+	}, WithDisallowUnknownFields()))
+
+	w := bindRequest(r, http.MethodGet, "/users?sort=name&active=true&unknown=1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"Limit":10,"Sort":"name","Active":true}`, w.Body.String())
+
+	w = bindRequest(r, http.MethodGet, "/users?limit=1000", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	err := readError(t, w)
+	assert.Equal(t, "limit", err.Field)
+	assert.Equal(t, "Payload is not valid : failed on the 'max=100' validation", err.Message)
+
+	w = bindRequest(r, http.MethodGet, "/users?limit=ten", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, errors.ErrCodeInvalidPayload, readError(t, w).Code)
+
+	w = bindRequest(r, http.MethodGet, "/strict/users?sort=name&unknown=1&other=1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	errs := readErrors(t, w)
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "other", errs[0].Field)
+	assert.Equal(t, "unknown", errs[1].Field)
+}
+
+func TestBindURI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/users/:id", BindURIHandler(func(ctx *gin.Context, params *testUserUri) {
+		ctx.JSON(http.StatusOK, params)
+	}))
+
+	w := bindRequest(r, http.MethodGet, "/users/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"Id":1}`, w.Body.String())
+
+	w = bindRequest(r, http.MethodGet, "/users/0", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "id", readError(t, w).Field)
+
+	w = bindRequest(r, http.MethodGet, "/users/abc", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
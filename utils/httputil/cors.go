@@ -0,0 +1,161 @@
+package httputil
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	OriginHeaderKey                        = "Origin"
+	VaryHeaderKey                          = "Vary"
+	AccessControlAllowOriginHeaderKey      = "Access-Control-Allow-Origin"
+	AccessControlAllowMethodsHeaderKey     = "Access-Control-Allow-Methods"
+	AccessControlAllowHeadersHeaderKey     = "Access-Control-Allow-Headers"
+	AccessControlExposeHeadersHeaderKey    = "Access-Control-Expose-Headers"
+	AccessControlAllowCredentialsHeaderKey = "Access-Control-Allow-Credentials"
+	AccessControlMaxAgeHeaderKey           = "Access-Control-Max-Age"
+	AccessControlRequestMethodHeaderKey    = "Access-Control-Request-Method"
+	AccessControlRequestHeadersHeaderKey   = "Access-Control-Request-Headers"
+
+	corsWildcard = "*"
+
+	corsCredentialsWildcardMsg = "CORS credentials cannot be allowed for any origin, configure the allowed origins instead of '*'"
+)
+
+var (
+	// DefaultCORSMethods are the methods that are allowed by CORS if no methods are configured.
+	DefaultCORSMethods = []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPost,
+		http.MethodPut,
+		http.MethodPatch,
+		http.MethodDelete,
+	}
+
+	// DefaultCORSHeaders are the headers that are allowed by CORS if no headers are configured.
+	DefaultCORSHeaders = []string{
+		OriginHeaderKey,
+		ContentTypeHeaderKey,
+		AcceptHeaderKey,
+		AuthorizationHeaderKey,
+		TraceIDHeaderKey,
+		ConsumerIdHeaderKey,
+	}
+)
+
+// CORSConfig represents the configuration of the CORS middleware.
+// An allowed origin can contain a wildcard, for example "https://*.example.com", or be "*" to allow any origin.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// NewCORSConfig returns the CORSConfig that is defined in the ServerConfig.
+func NewCORSConfig(sc *config.ServerConfig) CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   sc.CORSAllowedOrigins,
+		AllowedMethods:   sc.CORSAllowedMethods,
+		AllowedHeaders:   sc.CORSAllowedHeaders,
+		ExposedHeaders:   sc.CORSExposedHeaders,
+		AllowCredentials: sc.CORSAllowCredentials,
+		MaxAge:           sc.CORSMaxAge,
+	}
+}
+
+// CORS is a gin middleware that handles cross-origin requests.
+// Preflight requests of allowed origins are answered with a 204 response and preflight requests of origins
+// that are not allowed with a 403 response. Other requests are passed on, but only get the CORS headers
+// if the origin is allowed.
+// CORS panics if credentials are allowed for any origin, because that would allow any site to make
+// credentialed requests.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	if len(config.AllowedMethods) == 0 {
+		config.AllowedMethods = DefaultCORSMethods
+	}
+	if len(config.AllowedHeaders) == 0 {
+		config.AllowedHeaders = DefaultCORSHeaders
+	}
+	allowedMethods := strings.Join(config.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+	anyOrigin := slice.EntryExists(config.AllowedOrigins, corsWildcard)
+	anyHeader := slice.EntryExists(config.AllowedHeaders, corsWildcard)
+	if anyOrigin && config.AllowCredentials {
+		panic(corsCredentialsWildcardMsg)
+	}
+
+	return func(ctx *gin.Context) {
+		origin := ctx.GetHeader(OriginHeaderKey)
+		if origin == "" {
+			ctx.Next()
+			return
+		}
+		ctx.Writer.Header().Add(VaryHeaderKey, OriginHeaderKey)
+
+		preflight := ctx.Request.Method == http.MethodOptions && ctx.GetHeader(AccessControlRequestMethodHeaderKey) != ""
+		if !config.originAllowed(origin) {
+			if preflight {
+				ctx.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		if anyOrigin {
+			ctx.Header(AccessControlAllowOriginHeaderKey, corsWildcard)
+		} else {
+			ctx.Header(AccessControlAllowOriginHeaderKey, origin)
+		}
+		if config.AllowCredentials {
+			ctx.Header(AccessControlAllowCredentialsHeaderKey, "true")
+		}
+
+		if !preflight {
+			if exposedHeaders != "" {
+				ctx.Header(AccessControlExposeHeadersHeaderKey, exposedHeaders)
+			}
+			ctx.Next()
+			return
+		}
+
+		ctx.Header(AccessControlAllowMethodsHeaderKey, allowedMethods)
+		if anyHeader {
+			ctx.Header(AccessControlAllowHeadersHeaderKey, ctx.GetHeader(AccessControlRequestHeadersHeaderKey))
+		} else {
+			ctx.Header(AccessControlAllowHeadersHeaderKey, allowedHeaders)
+		}
+		if config.MaxAge > 0 {
+			ctx.Header(AccessControlMaxAgeHeaderKey, strconv.Itoa(int(config.MaxAge.Seconds())))
+		}
+		ctx.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// originAllowed checks if the origin matches one of the allowed origins.
+func (config CORSConfig) originAllowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range config.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == corsWildcard || allowed == origin {
+			return true
+		}
+		prefix, suffix, ok := strings.Cut(allowed, corsWildcard)
+		if ok && len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORS(t *testing.T) {
+	router := setupMockRouter(CORS(CORSConfig{
+		AllowedOrigins:   []string{"https://example.com", "https://*.example.org"},
+		ExposedHeaders:   []string{TraceIDHeaderKey},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		preflight   bool
+		statusCode  int
+		allowOrigin string
+	}{
+		{name: "no origin", method: http.MethodGet, statusCode: http.StatusOK},
+		{name: "allowed origin", method: http.MethodGet, origin: "https://example.com", statusCode: http.StatusOK, allowOrigin: "https://example.com"},
+		{name: "wildcard origin", method: http.MethodGet, origin: "https://app.example.org", statusCode: http.StatusOK, allowOrigin: "https://app.example.org"},
+		{name: "wildcard without subdomain", method: http.MethodGet, origin: "https://.example.org", statusCode: http.StatusOK},
+		{name: "not allowed origin", method: http.MethodGet, origin: "https://evil.com", statusCode: http.StatusOK},
+		{name: "preflight", method: http.MethodOptions, origin: "https://example.com", preflight: true, statusCode: http.StatusNoContent, allowOrigin: "https://example.com"},
+		{name: "preflight not allowed", method: http.MethodOptions, origin: "https://evil.com", preflight: true, statusCode: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, "/login", nil)
+			if tt.origin != "" {
+				req.Header.Set(OriginHeaderKey, tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set(AccessControlRequestMethodHeaderKey, http.MethodPost)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.allowOrigin, w.Header().Get(AccessControlAllowOriginHeaderKey))
+			if tt.allowOrigin == "" {
+				return
+			}
+			assert.Equal(t, "true", w.Header().Get(AccessControlAllowCredentialsHeaderKey))
+			if tt.preflight {
+				assert.Equal(t, "GET, HEAD, POST, PUT, PATCH, DELETE", w.Header().Get(AccessControlAllowMethodsHeaderKey))
+				assert.Equal(t, "Origin, Content-Type, Accept, Authorization, Trace-Id, Consumer-ID", w.Header().Get(AccessControlAllowHeadersHeaderKey))
+				assert.Equal(t, "600", w.Header().Get(AccessControlMaxAgeHeaderKey))
+			} else {
+				assert.Equal(t, TraceIDHeaderKey, w.Header().Get(AccessControlExposeHeadersHeaderKey))
+			}
+		})
+	}
+}
+
+func TestCORS_Wildcards(t *testing.T) {
+	router := setupMockRouter(CORS(CORSConfig{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{http.MethodGet},
+		AllowedHeaders: []string{"*"},
+	}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodOptions, "/login", nil)
+	req.Header.Set(OriginHeaderKey, "https://any.com")
+	req.Header.Set(AccessControlRequestMethodHeaderKey, http.MethodGet)
+	req.Header.Set(AccessControlRequestHeadersHeaderKey, "X-Custom")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get(AccessControlAllowOriginHeaderKey))
+	assert.Equal(t, http.MethodGet, w.Header().Get(AccessControlAllowMethodsHeaderKey))
+	assert.Equal(t, "X-Custom", w.Header().Get(AccessControlAllowHeadersHeaderKey))
+	assert.Empty(t, w.Header().Get(AccessControlAllowCredentialsHeaderKey))
+	assert.Empty(t, w.Header().Get(AccessControlMaxAgeHeaderKey))
+}
+
+func TestCORS_CredentialsForAnyOrigin(t *testing.T) {
+	assert.PanicsWithValue(t, corsCredentialsWildcardMsg, func() {
+		CORS(CORSConfig{AllowedOrigins: []string{"https://example.com", "*"}, AllowCredentials: true})
+	})
+	assert.NotPanics(t, func() {
+		CORS(CORSConfig{AllowedOrigins: []string{"https://*.example.com"}, AllowCredentials: true})
+	})
+}
+
+func TestNewCORSConfig(t *testing.T) {
+	sc := &config.ServerConfig{
+		CORSAllowedOrigins:   []string{"https://example.com"},
+		CORSAllowedMethods:   []string{http.MethodGet},
+		CORSAllowCredentials: true,
+		CORSMaxAge:           time.Hour,
+	}
+	assert.Equal(t, CORSConfig{
+		AllowedOrigins:   []string{"https://example.com"},
+		AllowedMethods:   []string{http.MethodGet},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}, NewCORSConfig(sc))
+}
@@ -0,0 +1,154 @@
+package httputil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/fileutil"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/security"
+)
+
+const (
+	htpasswdReloadErrMsg = "Unable to reload htpasswd file '%s', using the previously loaded credentials: %s"
+)
+
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+type (
+	// CredentialStore verifies the credentials of a user.
+	CredentialStore interface {
+		Authenticate(ctx context.Context, username, password string) bool
+	}
+
+	// CredentialStoreFunc is an adapter to allow the use of ordinary functions as CredentialStore.
+	CredentialStoreFunc func(ctx context.Context, username, password string) bool
+
+	// PlaintextCredentials is a CredentialStore with plaintext passwords mapped by username.
+	// Passwords are compared in constant time.
+	PlaintextCredentials map[string]string
+
+	// HashedCredentials is a CredentialStore with bcrypt or argon2id password hashes mapped by username.
+	// See security.HashPassword and security.HashPasswordArgon2id for creating the hashes.
+	HashedCredentials map[string]string
+
+	// HtpasswdFile is a CredentialStore that reads the credentials from a htpasswd file with
+	// bcrypt or argon2id password hashes. The file is reloaded when it changes.
+	HtpasswdFile struct {
+		path string
+
+		mu      sync.RWMutex
+		users   map[string]string
+		modTime time.Time
+		size    int64
+	}
+)
+
+// Authenticate calls f(ctx, username, password).
+func (f CredentialStoreFunc) Authenticate(ctx context.Context, username, password string) bool {
+	return f(ctx, username, password)
+}
+
+// Authenticate checks if the password of the user matches.
+func (c PlaintextCredentials) Authenticate(_ context.Context, username, password string) bool {
+	expected, ok := c[username]
+	return security.CompareSecrets(expected, password) && ok
+}
+
+// Authenticate checks if the password of the user matches the hash.
+func (c HashedCredentials) Authenticate(_ context.Context, username, password string) bool {
+	return compareHash(c, username, password)
+}
+
+// NewHtpasswdFile returns a CredentialStore that reads the credentials from the htpasswd file.
+// The method returns an error if the file cannot be read.
+func NewHtpasswdFile(filePath string) (*HtpasswdFile, *errors.Error) {
+	h := &HtpasswdFile{path: filePath}
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Authenticate checks if the password of the user matches the hash in the htpasswd file.
+// The file is reloaded first if it was modified since it was last loaded.
+func (h *HtpasswdFile) Authenticate(_ context.Context, username, password string) bool {
+	if h.changed() {
+		if err := h.load(); err != nil {
+			logger.Errorf(htpasswdReloadErrMsg, h.path, err.Message)
+		}
+	}
+	h.mu.RLock()
+	users := h.users
+	h.mu.RUnlock()
+	return compareHash(users, username, password)
+}
+
+// changed checks if the file was modified since it was last loaded.
+func (h *HtpasswdFile) changed() bool {
+	info, err := os.Stat(h.path)
+	if err != nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return !info.ModTime().Equal(h.modTime) || info.Size() != h.size
+}
+
+// load reads the users from the file.
+func (h *HtpasswdFile) load() *errors.Error {
+	info, sErr := os.Stat(h.path)
+	if sErr != nil {
+		return errors.Newf(errors.ErrCodeFileNotFound, 0, errors.ErrMsg[errors.ErrCodeFileNotFound], h.path)
+	}
+	data, err := fileutil.ReadFile(h.path)
+	if err != nil {
+		return err
+	}
+	users := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if username, hash, ok := strings.Cut(line, ":"); ok {
+			users[username] = hash
+		}
+	}
+	h.mu.Lock()
+	h.users = users
+	h.modTime = info.ModTime()
+	h.size = info.Size()
+	h.mu.Unlock()
+	return nil
+}
+
+// compareHash checks if the password matches the hash of the user.
+// If the user does not exist the password is compared with a dummy hash,
+// so that the response time does not reveal whether the user exists.
+func compareHash(hashes map[string]string, username, password string) bool {
+	hash, ok := hashes[username]
+	if !ok {
+		security.ComparePasswordHash(getDummyHash(), password)
+		return false
+	}
+	return security.ComparePasswordHash(hash, password)
+}
+
+// getDummyHash returns a bcrypt hash that is used to compare passwords of unknown users.
+func getDummyHash() string {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = security.HashPassword(security.GetRandomPassword())
+	})
+	return dummyHash
+}
@@ -0,0 +1,136 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/security"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMockHashedAccounts(t *testing.T) HashedCredentials {
+	bcryptHash, err := security.HashPassword("admin")
+	assert.Nil(t, err)
+	argon2idHash, err := security.HashPasswordArgon2id("user")
+	assert.Nil(t, err)
+	return HashedCredentials{
+		"admin": bcryptHash,
+		"user":  argon2idHash,
+	}
+}
+
+func TestPlaintextCredentials(t *testing.T) {
+	store := PlaintextCredentials(getMockAccount())
+	assert.True(t, store.Authenticate(context.Background(), "admin", "admin"))
+	assert.False(t, store.Authenticate(context.Background(), "admin", "user"))
+	assert.False(t, store.Authenticate(context.Background(), "unknown", ""))
+}
+
+func TestHashedCredentials(t *testing.T) {
+	store := getMockHashedAccounts(t)
+	assert.True(t, store.Authenticate(context.Background(), "admin", "admin"))
+	assert.True(t, store.Authenticate(context.Background(), "user", "user"))
+	assert.False(t, store.Authenticate(context.Background(), "admin", "user"))
+	assert.False(t, store.Authenticate(context.Background(), "unknown", "admin"))
+}
+
+func TestCredentialStoreFunc(t *testing.T) {
+	store := CredentialStoreFunc(func(_ context.Context, username, password string) bool {
+		return username == "admin" && password == "secret"
+	})
+	assert.True(t, store.Authenticate(context.Background(), "admin", "secret"))
+	assert.False(t, store.Authenticate(context.Background(), "admin", "admin"))
+}
+
+func TestHtpasswdFile(t *testing.T) {
+	accounts := getMockHashedAccounts(t)
+	path := filepath.Join(t.TempDir(), ".htpasswd")
+	content := "# users\n\nadmin:" + accounts["admin"] + "\ninvalid-line\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	store, cErr := NewHtpasswdFile(path)
+	assert.Nil(t, cErr)
+	assert.True(t, store.Authenticate(context.Background(), "admin", "admin"))
+	assert.False(t, store.Authenticate(context.Background(), "user", "user"))
+
+	t.Run("reload on change", func(t *testing.T) {
+		content += "user:" + accounts["user"] + "\n"
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		modTime := time.Now().Add(time.Second)
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+
+		assert.True(t, store.Authenticate(context.Background(), "user", "user"))
+		assert.True(t, store.Authenticate(context.Background(), "admin", "admin"))
+	})
+
+	t.Run("removed file keeps credentials", func(t *testing.T) {
+		assert.NoError(t, os.Remove(path))
+		assert.True(t, store.Authenticate(context.Background(), "admin", "admin"))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, cErr := NewHtpasswdFile(filepath.Join(t.TempDir(), "missing"))
+		assert.Nil(t, store)
+		assert.NotNil(t, cErr)
+	})
+}
+
+func TestBasicAuthWithStore(t *testing.T) {
+	router := setupMockRouter(setMockTraceId, BasicAuthWithStore(getMockHashedAccounts(t)))
+
+	tests := []struct {
+		name       string
+		username   string
+		password   string
+		noAuth     bool
+		statusCode int
+		response   string
+	}{
+		{name: "bcrypt", username: "admin", password: "admin", statusCode: http.StatusOK, response: "OK"},
+		{name: "argon2id", username: "user", password: "user", statusCode: http.StatusOK, response: "OK"},
+		{name: "invalid password", username: "admin", password: "user", statusCode: http.StatusUnauthorized, response: invalidAuthResponse},
+		{name: "unknown user", username: "unknown", password: "admin", statusCode: http.StatusUnauthorized, response: invalidAuthResponse},
+		{name: "no auth", noAuth: true, statusCode: http.StatusUnauthorized, response: noAuthResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+			if !tt.noAuth {
+				req.SetBasicAuth(tt.username, tt.password)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestBasicAuthWithStore_Context(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	var received context.Context
+	r.Use(BasicAuthWithStore(CredentialStoreFunc(func(ctx context.Context, _, _ string) bool {
+		received = ctx
+		return true
+	})))
+	r.GET("/login", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString(AuthUserKey))
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+	req.SetBasicAuth("admin", "admin")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "admin", w.Body.String())
+	assert.NotNil(t, received)
+}
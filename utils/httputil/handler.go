@@ -0,0 +1,191 @@
+package httputil
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/structutil"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+var (
+	// NegotiatedMIMETypes are the media types of the responses of a handler with content negotiation.
+	// The first media type is used if the request does not have an Accept header.
+	NegotiatedMIMETypes = []string{
+		ApplicationJsonMIMEType,
+		ApplicationYamlMIMEType,
+		binding.MIMEYAML,
+		ApplicationXmlMIMEType,
+		binding.MIMEXML2,
+	}
+)
+
+type (
+	// HandlerConfig represents the configuration of a typed handler.
+	HandlerConfig struct {
+		Status             int
+		ContentNegotiation bool
+		BindOptions        []BindOption
+	}
+
+	// HandlerOption is an option that can be used to configure a typed handler.
+	HandlerOption func(config *HandlerConfig)
+
+	// HandlerFunc is a handler that receives the bound request and returns the response or an error.
+	HandlerFunc[Req, Resp any] func(ctx *gin.Context, req Req) (Resp, *errors.Error)
+)
+
+// WithStatus is a Handle option that defines the status of successful responses.
+func WithStatus(status int) HandlerOption {
+	return func(config *HandlerConfig) {
+		config.Status = status
+	}
+}
+
+// WithContentNegotiation is a Handle option that renders the responses and errors as JSON, YAML or XML,
+// based on the Accept header of the request, see NegotiatedMIMETypes.
+// Requests that do not accept any of the media types are rejected with a ErrCodeNotAcceptable error.
+// Responses that cannot be encoded as XML, like maps, are rendered as JSON.
+func WithContentNegotiation() HandlerOption {
+	return func(config *HandlerConfig) {
+		config.ContentNegotiation = true
+	}
+}
+
+// WithBindOptions is a Handle option that defines the options that are used to bind the request.
+func WithBindOptions(opts ...BindOption) HandlerOption {
+	return func(config *HandlerConfig) {
+		config.BindOptions = opts
+	}
+}
+
+// Handle turns a typed handler into a gin handler.
+// The request is bound to a new Req: the defaults of the default tags are applied, the path parameters are mapped
+// using the uri tags, the query parameters using the form tags and the JSON body, if the request has a body, is
+// decoded. The result is validated with the binding tags. Binding errors are returned as described for BindJSON.
+// The response is rendered with the status of WithStatus. If it is not set, the status is 204 No Content if the
+// response is nil or an empty struct, 201 Created for POST requests and 200 OK otherwise.
+// An error returned by the handler is rendered with the trace id of the request, see AbortWithError.
+func Handle[Req, Resp any](handler HandlerFunc[Req, Resp], opts ...HandlerOption) gin.HandlerFunc {
+	config := new(HandlerConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+	bindConfig := newBindConfig(config.BindOptions)
+
+	return func(ctx *gin.Context) {
+		format := ApplicationJsonMIMEType
+		if config.ContentNegotiation {
+			if format = ctx.NegotiateFormat(NegotiatedMIMETypes...); format == "" {
+				AbortWithError(ctx, errors.Newf(errors.ErrCodeNotAcceptable, http.StatusNotAcceptable,
+					errors.ErrMsg[errors.ErrCodeNotAcceptable], NegotiatedMIMETypes))
+				return
+			}
+		}
+
+		req := new(Req)
+		if errs := bindHandlerRequest(ctx, req, bindConfig); errs != nil {
+			abortWithFormat(ctx, format, errs...)
+			return
+		}
+		resp, err := handler(ctx, *req)
+		if err != nil {
+			abortWithFormat(ctx, format, err)
+			return
+		}
+
+		status := config.Status
+		if status == 0 {
+			status = responseStatus(ctx.Request.Method, resp)
+		}
+		if status == http.StatusNoContent {
+			ctx.Status(status)
+			return
+		}
+		render(ctx, format, status, resp)
+	}
+}
+
+// bindHandlerRequest binds the path parameters, the query parameters and the JSON body to the request struct.
+// Requests that are not a struct are not bound.
+func bindHandlerRequest(ctx *gin.Context, req any, config *BindConfig) []*errors.Error {
+	t := reflect.TypeOf(req).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if err := structutil.SetDefaults(req); err != nil {
+		return []*errors.Error{err}
+	}
+	if hasTag(t, bindTagUri) {
+		params := make(map[string][]string, len(ctx.Params))
+		for _, p := range ctx.Params {
+			params[p.Key] = []string{p.Value}
+		}
+		if err := binding.MapFormWithTag(req, params, bindTagUri); err != nil {
+			return []*errors.Error{invalidPayloadError("", err.Error())}
+		}
+	}
+	if hasTag(t, bindTagForm) {
+		query := ctx.Request.URL.Query()
+		if config.DisallowUnknownFields {
+			if errs := unknownFields(t, query, bindTagForm); errs != nil {
+				return errs
+			}
+		}
+		if err := binding.MapFormWithTag(req, query, bindTagForm); err != nil {
+			return []*errors.Error{invalidPayloadError("", err.Error())}
+		}
+	}
+	if ctx.Request.Body != nil && ctx.Request.Body != http.NoBody {
+		if err := decodeJSON(ctx.Request.Body, req, config); err != nil {
+			return []*errors.Error{err}
+		}
+	}
+	return validateStruct(req, structutil.StructTagJson, bindTagForm, bindTagUri)
+}
+
+// hasTag checks if any of the fields of the struct type has the tag.
+func hasTag(t reflect.Type, tag string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// responseStatus returns the status of a successful response.
+func responseStatus(method string, resp any) int {
+	v := reflect.ValueOf(resp)
+	switch {
+	case !v.IsValid(),
+		(v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil(),
+		v.Kind() == reflect.Struct && v.NumField() == 0:
+		return http.StatusNoContent
+	case method == http.MethodPost:
+		return http.StatusCreated
+	default:
+		return http.StatusOK
+	}
+}
+
+// render renders the data in the format.
+// Data that cannot be encoded as XML, like maps, is rendered as JSON instead.
+func render(ctx *gin.Context, format string, status int, data any) {
+	switch format {
+	case ApplicationYamlMIMEType, binding.MIMEYAML:
+		ctx.YAML(status, data)
+	case ApplicationXmlMIMEType, binding.MIMEXML2:
+		body, err := xml.Marshal(data)
+		if err != nil {
+			ctx.JSON(status, data)
+			return
+		}
+		ctx.Data(status, ApplicationXmlMIMEType+"; charset=utf-8", body)
+	default:
+		ctx.JSON(status, data)
+	}
+}
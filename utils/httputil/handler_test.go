@@ -0,0 +1,183 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	testUserRequest struct {
+		Id      int    `uri:"id" binding:"required,min=1"`
+		Verbose bool   `form:"verbose"`
+		Fields  string `form:"fields" default:"all"`
+		Name    string `json:"name" binding:"omitempty,min=2"`
+	}
+
+	testUserResponse struct {
+		Id      int    `json:"id" yaml:"id" xml:"id"`
+		Name    string `json:"name" yaml:"name" xml:"name"`
+		Fields  string `json:"fields" yaml:"fields" xml:"fields"`
+		Verbose bool   `json:"verbose" yaml:"verbose" xml:"verbose"`
+	}
+)
+
+func newHandlerRouter(opts ...HandlerOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/users/:id", Handle(func(ctx *gin.Context, req testUserRequest) (*testUserResponse, *errors.Error) {
+		if req.Id == 404 {
+			return nil, errors.NotFoundError("user was not found")
+		}
+		return &testUserResponse{Id: req.Id, Name: "test", Fields: req.Fields, Verbose: req.Verbose}, nil
+	}, opts...))
+	r.POST("/users/:id", Handle(func(ctx *gin.Context, req testUserRequest) (testUserResponse, *errors.Error) {
+		return testUserResponse{Id: req.Id, Name: req.Name}, nil
+	}, opts...))
+	r.PUT("/users/:id", Handle(func(ctx *gin.Context, req testUserRequest) (testUserResponse, *errors.Error) {
+		return testUserResponse{Id: req.Id, Name: req.Name}, nil
+	}, append(opts, WithStatus(http.StatusAccepted))...))
+	r.DELETE("/users/:id", Handle(func(ctx *gin.Context, req testUserRequest) (struct{}, *errors.Error) {
+		return struct{}{}, nil
+	}, opts...))
+	r.GET("/ping", Handle(func(ctx *gin.Context, req struct{}) (string, *errors.Error) {
+		return "pong", nil
+	}, opts...))
+	r.GET("/settings", Handle(func(ctx *gin.Context, req struct{}) (map[string]any, *errors.Error) {
+		return map[string]any{"theme": "dark"}, nil
+	}, opts...))
+	return r
+}
+
+func serveHandler(r *gin.Engine, method, path, body, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	var req *http.Request
+	if body == "" {
+		req, _ = http.NewRequest(method, path, nil)
+	} else {
+		req, _ = http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(ContentTypeHeaderKey, ApplicationJsonMIMEType)
+	}
+	if accept != "" {
+		req.Header.Set(AcceptHeaderKey, accept)
+	}
+	req.Header.Set(TraceIDHeaderKey, "trace")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandle(t *testing.T) {
+	r := newHandlerRouter()
+
+	t.Run("get", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/1?verbose=true", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"id":1,"name":"test","fields":"all","verbose":true}`, w.Body.String())
+	})
+
+	t.Run("post", func(t *testing.T) {
+		w := serveHandler(r, http.MethodPost, "/users/1", `{"name":"user"}`, "")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"id":1,"name":"user","fields":"","verbose":false}`, w.Body.String())
+	})
+
+	t.Run("status", func(t *testing.T) {
+		w := serveHandler(r, http.MethodPut, "/users/1", `{"name":"user"}`, "")
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		w := serveHandler(r, http.MethodDelete, "/users/1", "", "")
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("request that is not a struct", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/ping", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"pong"`, w.Body.String())
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/404", "", "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		err := readError(t, w)
+		assert.Equal(t, errors.ErrCodeNotFound, err.Code)
+		assert.Equal(t, "trace", err.TraceId)
+	})
+
+	t.Run("binding errors", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/0", "", "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		err := readError(t, w)
+		assert.Equal(t, errors.ErrCodeInvalidPayload, err.Code)
+		assert.Equal(t, "id", err.Field)
+
+		w = serveHandler(r, http.MethodPost, "/users/1", `{"name":"u"}`, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "name", readError(t, w).Field)
+
+		w = serveHandler(r, http.MethodPost, "/users/1", `{"name":`, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, requestBodyField, readError(t, w).Field)
+	})
+
+	t.Run("accept header is ignored", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/1", "", ApplicationXmlMIMEType)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get(ContentTypeHeaderKey), ApplicationJsonMIMEType)
+	})
+}
+
+func TestHandleContentNegotiation(t *testing.T) {
+	r := newHandlerRouter(WithContentNegotiation())
+
+	t.Run("json", func(t *testing.T) {
+		for _, accept := range []string{"", "*/*", ApplicationJsonMIMEType} {
+			w := serveHandler(r, http.MethodGet, "/users/1", "", accept)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get(ContentTypeHeaderKey), ApplicationJsonMIMEType)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/1", "", ApplicationYamlMIMEType)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get(ContentTypeHeaderKey), "yaml")
+		assert.Equal(t, "id: 1\nname: test\nfields: all\nverbose: false\n", w.Body.String())
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/1", "", "text/xml")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get(ContentTypeHeaderKey), ApplicationXmlMIMEType)
+		assert.Equal(t,
+			"<testUserResponse><id>1</id><name>test</name><fields>all</fields><verbose>false</verbose></testUserResponse>",
+			w.Body.String())
+	})
+
+	t.Run("xml not supported by the response", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/settings", "", ApplicationXmlMIMEType)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get(ContentTypeHeaderKey), ApplicationJsonMIMEType)
+		assert.JSONEq(t, `{"theme":"dark"}`, w.Body.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/404", "", ApplicationYamlMIMEType)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "code: NOT_FOUND")
+		assert.Contains(t, w.Body.String(), "traceid: trace")
+	})
+
+	t.Run("not acceptable", func(t *testing.T) {
+		w := serveHandler(r, http.MethodGet, "/users/1", "", "text/html")
+		assert.Equal(t, http.StatusNotAcceptable, w.Code)
+		assert.Equal(t, errors.ErrCodeNotAcceptable, readError(t, w).Code)
+	})
+}
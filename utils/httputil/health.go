@@ -0,0 +1,301 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthLivePath  = "/live"
+	HealthReadyPath = "/ready"
+
+	HealthStatusUp       = "UP"
+	HealthStatusDegraded = "DEGRADED"
+	HealthStatusDown     = "DOWN"
+
+	healthShutdownMsg         = "Server is shutting down"
+	defaultHealthCheckTimeout = 5 * time.Second
+	defaultHealthCacheTTL     = 5 * time.Second
+)
+
+type (
+	// HealthCheckFunc checks the health of a component. The check should return an error if
+	// the component is not healthy and should stop when the context is done.
+	HealthCheckFunc func(ctx context.Context) error
+
+	// HealthCheck represents a named health check of a component.
+	// If a critical check fails the service is not ready, if a non-critical check fails the service is degraded.
+	HealthCheck struct {
+		Name     string
+		Check    HealthCheckFunc
+		Timeout  time.Duration
+		Critical bool
+	}
+
+	// HealthCheckOption is an option that can be used to configure a HealthCheck.
+	HealthCheckOption func(check *HealthCheck)
+
+	// HealthCheckResult represents the result of a HealthCheck.
+	HealthCheckResult struct {
+		Name      string    `json:"name"`
+		Status    string    `json:"status"`
+		Critical  bool      `json:"critical"`
+		LatencyMs float64   `json:"latencyMs"`
+		Error     string    `json:"error,omitempty"`
+		CheckedAt time.Time `json:"checkedAt"`
+	}
+
+	// HealthReport represents the health of the service and the results of the health checks.
+	HealthReport struct {
+		Status string              `json:"status"`
+		Checks []HealthCheckResult `json:"checks,omitempty"`
+	}
+
+	// HealthRegistry keeps the health checks of the service.
+	// The results of the checks are cached to avoid overloading the dependencies of the service.
+	HealthRegistry struct {
+		cacheTTL     time.Duration
+		shuttingDown atomic.Bool
+
+		mu       sync.RWMutex
+		checks   []HealthCheck
+		results  map[string]HealthCheckResult
+		inflight map[string]*healthCall
+	}
+
+	// healthCall is a run of a health check that is shared by the callers that need its result.
+	healthCall struct {
+		done   chan struct{}
+		result HealthCheckResult
+	}
+
+	// HealthOption is an option that can be used to configure a HealthRegistry.
+	HealthOption func(h *HealthRegistry)
+)
+
+// WithCheckTimeout is a HealthCheck option that defines the timeout of the check. Default timeout is 5 seconds.
+func WithCheckTimeout(timeout time.Duration) HealthCheckOption {
+	return func(check *HealthCheck) {
+		check.Timeout = timeout
+	}
+}
+
+// NonCritical is a HealthCheck option that marks the check as non-critical.
+// A failing non-critical check does not make the service unavailable. Checks are critical by default.
+func NonCritical() HealthCheckOption {
+	return func(check *HealthCheck) {
+		check.Critical = false
+	}
+}
+
+// WithHealthCacheTTL is a HealthRegistry option that defines how long the results of the checks are cached.
+// Default TTL is 5 seconds, a TTL of zero disables caching.
+func WithHealthCacheTTL(ttl time.Duration) HealthOption {
+	return func(h *HealthRegistry) {
+		h.cacheTTL = ttl
+	}
+}
+
+// NewHealthRegistry returns a new HealthRegistry without checks.
+func NewHealthRegistry(opts ...HealthOption) *HealthRegistry {
+	h := &HealthRegistry{
+		cacheTTL: defaultHealthCacheTTL,
+		results:  make(map[string]HealthCheckResult),
+		inflight: make(map[string]*healthCall),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Register registers a named health check. A check with the same name is replaced.
+func (h *HealthRegistry) Register(name string, check HealthCheckFunc, opts ...HealthCheckOption) {
+	hc := HealthCheck{Name: name, Check: check, Timeout: defaultHealthCheckTimeout, Critical: true}
+	for _, opt := range opts {
+		opt(&hc)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.results, name)
+	delete(h.inflight, name)
+	for i := range h.checks {
+		if h.checks[i].Name == name {
+			h.checks[i] = hc
+			return
+		}
+	}
+	h.checks = append(h.checks, hc)
+}
+
+// Shutdown marks the service as shutting down, from then on the service is reported as not ready.
+// Shutdown should be called before the server is shut down, so that no new traffic is sent to the service.
+func (h *HealthRegistry) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Check runs the health checks concurrently and returns the report.
+// The status of the report is:
+//   - DOWN if a critical check failed.
+//   - DEGRADED if a non-critical check failed.
+//   - UP otherwise.
+func (h *HealthRegistry) Check(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := make([]HealthCheck, len(h.checks))
+	copy(checks, h.checks)
+	h.mu.RUnlock()
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthCheck) {
+			defer wg.Done()
+			results[i] = h.result(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: HealthStatusUp, Checks: results}
+	for _, r := range results {
+		if r.Status == HealthStatusUp {
+			continue
+		}
+		if r.Critical {
+			report.Status = HealthStatusDown
+			break
+		}
+		report.Status = HealthStatusDegraded
+	}
+	return report
+}
+
+// result returns the cached result of the check or runs the check if there is no valid cached result.
+// Concurrent callers share a single run of the check. The check runs until its timeout even if the context
+// of the caller is done, so that the result can be cached and shared, but the caller stops waiting for it.
+func (h *HealthRegistry) result(ctx context.Context, check HealthCheck) HealthCheckResult {
+	start := time.Now()
+	h.mu.Lock()
+	if cached, ok := h.results[check.Name]; ok && time.Since(cached.CheckedAt) < h.cacheTTL {
+		h.mu.Unlock()
+		return cached
+	}
+	if ctx.Err() != nil {
+		h.mu.Unlock()
+		return cancelledHealthResult(ctx, check, start)
+	}
+	call, ok := h.inflight[check.Name]
+	if !ok {
+		call = &healthCall{done: make(chan struct{})}
+		h.inflight[check.Name] = call
+		go h.run(context.WithoutCancel(ctx), check, call)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.result
+	case <-ctx.Done():
+		return cancelledHealthResult(ctx, check, start)
+	}
+}
+
+// cancelledHealthResult returns the result of the check for a caller whose context is done.
+func cancelledHealthResult(ctx context.Context, check HealthCheck, start time.Time) HealthCheckResult {
+	return HealthCheckResult{
+		Name:      check.Name,
+		Status:    HealthStatusDown,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Error:     ctx.Err().Error(),
+		CheckedAt: start,
+	}
+}
+
+// run runs the check of the call and caches the result, unless the check was registered again in the meantime.
+func (h *HealthRegistry) run(ctx context.Context, check HealthCheck, call *healthCall) {
+	call.result = runHealthCheck(ctx, check)
+
+	h.mu.Lock()
+	if h.inflight[check.Name] == call {
+		delete(h.inflight, check.Name)
+		h.results[check.Name] = call.result
+	}
+	h.mu.Unlock()
+	close(call.done)
+}
+
+// runHealthCheck runs the check with its timeout.
+func runHealthCheck(ctx context.Context, check HealthCheck) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health check panicked: %v", r)
+			}
+		}()
+		done <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := HealthCheckResult{
+		Name:      check.Name,
+		Status:    HealthStatusUp,
+		Critical:  check.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Live controller reports if the service is running. The service is live as long as it can handle requests.
+func (h *HealthRegistry) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, HealthReport{Status: HealthStatusUp})
+}
+
+// Ready controller reports if the service is ready to handle traffic.
+// The controller responds with 503 Service Unavailable if a critical check failed or the service is shutting down.
+func (h *HealthRegistry) Ready(ctx *gin.Context) {
+	if h.shuttingDown.Load() {
+		ctx.JSON(http.StatusServiceUnavailable, HealthReport{
+			Status: HealthStatusDown,
+			Checks: []HealthCheckResult{{Name: "shutdown", Status: HealthStatusDown, Critical: true, Error: healthShutdownMsg, CheckedAt: time.Now()}},
+		})
+		return
+	}
+	report := h.Check(ctx.Request.Context())
+	status := http.StatusOK
+	if report.Status == HealthStatusDown {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
+
+// RegisterRoutes registers the Ready controller on ApiHealthPath and ApiHealthPath + HealthReadyPath
+// and the Live controller on ApiHealthPath + HealthLivePath.
+func (h *HealthRegistry) RegisterRoutes(r gin.IRoutes) {
+	r.GET(ApiHealthPath, h.Ready)
+	r.GET(ApiHealthPath+HealthLivePath, h.Live)
+	r.GET(ApiHealthPath+HealthReadyPath, h.Ready)
+}
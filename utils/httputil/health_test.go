@@ -0,0 +1,171 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getHealthReport(t *testing.T, h *HealthRegistry, path string) (int, HealthReport) {
+	r := NewRouter(WithHealthRegistry(h))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+
+	var report HealthReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	return w.Code, report
+}
+
+func TestHealthRegistry_Check(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return fmt.Errorf("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		register func(h *HealthRegistry)
+		status   string
+	}{
+		{name: "no checks", register: func(h *HealthRegistry) {}, status: HealthStatusUp},
+		{name: "up", register: func(h *HealthRegistry) {
+			h.Register("db", up)
+		}, status: HealthStatusUp},
+		{name: "critical down", register: func(h *HealthRegistry) {
+			h.Register("db", down)
+			h.Register("cache", up, NonCritical())
+		}, status: HealthStatusDown},
+		{name: "non-critical down", register: func(h *HealthRegistry) {
+			h.Register("db", up)
+			h.Register("cache", down, NonCritical())
+		}, status: HealthStatusDegraded},
+		{name: "timeout", register: func(h *HealthRegistry) {
+			h.Register("db", slow, WithCheckTimeout(10*time.Millisecond))
+		}, status: HealthStatusDown},
+		{name: "panic", register: func(h *HealthRegistry) {
+			h.Register("db", func(context.Context) error { panic("boom") })
+		}, status: HealthStatusDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthRegistry()
+			tt.register(h)
+			assert.Equal(t, tt.status, h.Check(context.Background()).Status)
+		})
+	}
+}
+
+func TestHealthRegistry_Cache(t *testing.T) {
+	var calls atomic.Int32
+	check := func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}
+
+	h := NewHealthRegistry(WithHealthCacheTTL(time.Minute))
+	h.Register("db", check)
+	h.Check(context.Background())
+	h.Check(context.Background())
+	assert.Equal(t, int32(1), calls.Load())
+
+	h.Register("db", check)
+	h.Check(context.Background())
+	assert.Equal(t, int32(2), calls.Load())
+
+	h = NewHealthRegistry(WithHealthCacheTTL(0))
+	h.Register("db", check)
+	h.Check(context.Background())
+	h.Check(context.Background())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestHealthRegistry_CacheCancelled(t *testing.T) {
+	h := NewHealthRegistry(WithHealthCacheTTL(time.Minute))
+	h.Register("db", func(ctx context.Context) error {
+		return ctx.Err()
+	})
+
+	// the check failed because the caller went away, which must not be cached
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, HealthStatusDown, h.Check(ctx).Status)
+	assert.Equal(t, HealthStatusUp, h.Check(context.Background()).Status)
+}
+
+func TestHealthRegistry_CacheConcurrent(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	h := NewHealthRegistry(WithHealthCacheTTL(time.Minute))
+	h.Register("db", func(context.Context) error {
+		calls.Add(1)
+		<-release
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	reports := make([]HealthReport, 10)
+	for i := range reports {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reports[i] = h.Check(context.Background())
+		}(i)
+	}
+	// the callers that arrive while the check runs must wait for its result
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, report := range reports {
+		assert.Equal(t, HealthStatusUp, report.Status)
+	}
+}
+
+func TestHealthRegistry_Routes(t *testing.T) {
+	h := NewHealthRegistry()
+	h.Register("db", func(context.Context) error { return fmt.Errorf("connection refused") })
+	h.Register("cache", func(context.Context) error { return nil }, NonCritical())
+
+	code, report := getHealthReport(t, h, ApiHealthPath+HealthLivePath)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusUp, report.Status)
+
+	code, report = getHealthReport(t, h, ApiHealthPath+HealthReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusDown, report.Status)
+	assert.Len(t, report.Checks, 2)
+	assert.Equal(t, "db", report.Checks[0].Name)
+	assert.Equal(t, "connection refused", report.Checks[0].Error)
+	assert.True(t, report.Checks[0].Critical)
+	assert.Equal(t, HealthStatusUp, report.Checks[1].Status)
+
+	code, _ = getHealthReport(t, h, ApiHealthPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestHealthRegistry_Shutdown(t *testing.T) {
+	h := NewHealthRegistry()
+
+	code, _ := getHealthReport(t, h, ApiHealthPath+HealthReadyPath)
+	assert.Equal(t, http.StatusOK, code)
+
+	h.Shutdown()
+	code, report := getHealthReport(t, h, ApiHealthPath+HealthReadyPath)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusDown, report.Status)
+
+	code, _ = getHealthReport(t, h, ApiHealthPath+HealthLivePath)
+	assert.Equal(t, http.StatusOK, code)
+}
@@ -0,0 +1,382 @@
+package httputil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/fileutil"
+)
+
+const (
+	defaultJWKSRefreshInterval    = time.Hour
+	defaultJWKSMinRefreshInterval = time.Minute
+	defaultJWKSTimeout            = 10 * time.Second
+)
+
+type (
+	// KeyProvider provides the keys that are used to verify the signature of tokens.
+	// Key returns the key for the key id and algorithm of the token header.
+	KeyProvider interface {
+		Key(ctx context.Context, kid, alg string) (any, error)
+	}
+
+	// JWK represents a JSON Web Key.
+	JWK struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+		K   string `json:"k"`
+	}
+
+	// JWKSet represents a JSON Web Key Set.
+	JWKSet struct {
+		Keys []JWK `json:"keys"`
+	}
+
+	// signingKey is a parsed verification key.
+	signingKey struct {
+		kid string
+		alg string
+		key any
+	}
+
+	// StaticKeyProvider is a KeyProvider with a fixed set of keys.
+	StaticKeyProvider struct {
+		keys []signingKey
+	}
+
+	// JWKSKeyProvider is a KeyProvider that fetches the keys from a JWKS url and caches them.
+	// The keys are refreshed after the refresh interval or when a token references an unknown key id.
+	// The keys are fetched at most once per minimum refresh interval, also if the previous fetch failed.
+	JWKSKeyProvider struct {
+		url                string
+		client             *http.Client
+		refreshInterval    time.Duration
+		minRefreshInterval time.Duration
+
+		// fetchMu makes sure only one fetch runs at a time, without blocking the readers of the cached keys
+		fetchMu sync.Mutex
+
+		mu          sync.RWMutex
+		keys        []signingKey
+		fetchedAt   time.Time
+		attemptedAt time.Time
+		fetchErr    error
+	}
+
+	// JWKSOption is an option that can be used to configure the JWKSKeyProvider.
+	JWKSOption func(p *JWKSKeyProvider)
+)
+
+// NewHMACKeyProvider returns a KeyProvider for tokens that are signed with a shared secret (HS256).
+func NewHMACKeyProvider(secret []byte) *StaticKeyProvider {
+	return &StaticKeyProvider{keys: []signingKey{{alg: "HS256", key: secret}}}
+}
+
+// NewKeyFileProvider returns a KeyProvider with the keys from a local key file.
+// The file can either contain a JWKS document or a PEM encoded public key or certificate.
+// The method returns an error if the file cannot be read or does not contain a valid key.
+func NewKeyFileProvider(filePath string) (*StaticKeyProvider, *errors.Error) {
+	data, err := fileutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var keys []signingKey
+	var pErr error
+	if block, _ := pem.Decode(data); block != nil {
+		keys, pErr = parsePEMKey(block)
+	} else {
+		keys, pErr = parseJWKS(data)
+	}
+	if pErr != nil {
+		return nil, errors.Newf(
+			errors.ErrCodeInvalidSigningKey,
+			0,
+			errors.ErrMsg[errors.ErrCodeInvalidSigningKey],
+			pErr.Error(),
+		)
+	}
+	return &StaticKeyProvider{keys: keys}, nil
+}
+
+// Key returns the key that matches the key id and algorithm.
+func (p *StaticKeyProvider) Key(_ context.Context, kid, alg string) (any, error) {
+	return selectKey(p.keys, kid, alg)
+}
+
+// WithJWKSHTTPClient is a JWKSOption that can be used to define the http client that fetches the keys.
+func WithJWKSHTTPClient(client *http.Client) JWKSOption {
+	return func(p *JWKSKeyProvider) {
+		p.client = client
+	}
+}
+
+// WithJWKSRefreshInterval is a JWKSOption that can be used to define how long the keys are cached.
+// Default interval is 1 hour.
+func WithJWKSRefreshInterval(interval time.Duration) JWKSOption {
+	return func(p *JWKSKeyProvider) {
+		p.refreshInterval = interval
+	}
+}
+
+// WithJWKSMinRefreshInterval is a JWKSOption that can be used to define the minimum time between two fetches
+// that are triggered by unknown key ids. Default interval is 1 minute.
+func WithJWKSMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(p *JWKSKeyProvider) {
+		p.minRefreshInterval = interval
+	}
+}
+
+// NewJWKSKeyProvider returns a KeyProvider that fetches the keys from the JWKS url.
+func NewJWKSKeyProvider(url string, opts ...JWKSOption) *JWKSKeyProvider {
+	p := &JWKSKeyProvider{
+		url:                url,
+		client:             &http.Client{Timeout: defaultJWKSTimeout},
+		refreshInterval:    defaultJWKSRefreshInterval,
+		minRefreshInterval: defaultJWKSMinRefreshInterval,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Key returns the key that matches the key id and algorithm.
+// The keys are fetched if the cache is empty or expired, or if the key id is unknown.
+func (p *JWKSKeyProvider) Key(ctx context.Context, kid, alg string) (any, error) {
+	p.mu.RLock()
+	keys, fetchedAt := p.keys, p.fetchedAt
+	p.mu.RUnlock()
+
+	age := time.Since(fetchedAt)
+	if keys != nil && age < p.refreshInterval {
+		if key, err := selectKey(keys, kid, alg); err == nil || age < p.minRefreshInterval {
+			return key, err
+		}
+	}
+
+	refreshed, err := p.refresh(ctx)
+	if err != nil {
+		if keys == nil {
+			return nil, err
+		}
+		// keep using the cached keys if the JWKS url is temporarily unavailable
+		refreshed = keys
+	}
+	return selectKey(refreshed, kid, alg)
+}
+
+// refresh fetches the keys from the JWKS url and updates the cache.
+// If the keys were fetched, or the fetch failed, within the minimum refresh interval, the result of that
+// fetch is returned instead. The fetch does not stop if the request that triggered it is cancelled,
+// because the result is shared with all requests.
+func (p *JWKSKeyProvider) refresh(ctx context.Context) ([]signingKey, error) {
+	p.fetchMu.Lock()
+	defer p.fetchMu.Unlock()
+
+	// another request might have refreshed the keys while waiting for the lock
+	p.mu.RLock()
+	keys, attemptedAt, fetchErr := p.keys, p.attemptedAt, p.fetchErr
+	p.mu.RUnlock()
+	if !attemptedAt.IsZero() && time.Since(attemptedAt) < p.minRefreshInterval {
+		return keys, fetchErr
+	}
+
+	keys, err := p.fetch(context.WithoutCancel(ctx))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attemptedAt = time.Now()
+	p.fetchErr = err
+	if err != nil {
+		return nil, err
+	}
+	p.keys = keys
+	p.fetchedAt = p.attemptedAt
+	return keys, nil
+}
+
+// fetch fetches the keys from the JWKS url. Symmetric keys are rejected, because a public key set
+// must not provide shared secrets.
+func (p *JWKSKeyProvider) fetch(ctx context.Context) ([]signingKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(AcceptHeaderKey, ApplicationJsonMIMEType)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var set JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("unable to decode JWKS: %w", err)
+	}
+	return set.signingKeys(false)
+}
+
+// selectKey returns the key that matches the key id and algorithm.
+// Keys without a key id match any key id. If the key id is empty the first key that is
+// compatible with the algorithm is returned.
+func selectKey(keys []signingKey, kid, alg string) (any, error) {
+	for _, k := range keys {
+		if kid != "" && k.kid != "" && k.kid != kid {
+			continue
+		}
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		if !keyMatchesAlg(k.key, alg) {
+			continue
+		}
+		return k.key, nil
+	}
+	return nil, fmt.Errorf("no key found for kid '%s' and alg '%s'", kid, alg)
+}
+
+// keyMatchesAlg checks if the key type can be used for the signing algorithm.
+func keyMatchesAlg(key any, alg string) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	case []byte:
+		return strings.HasPrefix(alg, "HS")
+	}
+	return false
+}
+
+// parseJWKS parses a JWKS document.
+func parseJWKS(data []byte) ([]signingKey, error) {
+	var set JWKSet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	return set.signingKeys(true)
+}
+
+// signingKeys parses the keys of the set that can be used to verify signatures.
+// Symmetric (oct) keys are only accepted if allowSecrets is true.
+func (s JWKSet) signingKeys(allowSecrets bool) ([]signingKey, error) {
+	keys := make([]signingKey, 0, len(s.Keys))
+	for _, jwk := range s.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if jwk.Kty == "oct" && !allowSecrets {
+			return nil, fmt.Errorf("invalid key '%s': symmetric keys are not allowed", jwk.Kid)
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key '%s': %w", jwk.Kid, err)
+		}
+		keys = append(keys, signingKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found")
+	}
+	return keys, nil
+}
+
+// publicKey returns the verification key that is represented by the JWK.
+func (jwk JWK) publicKey() (any, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(jwk.K)
+	}
+	return nil, fmt.Errorf("unsupported key type '%s'", jwk.Kty)
+}
+
+// decodeBigInt decodes a base64url encoded big-endian integer.
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// parsePEMKey parses a PEM encoded public key or certificate.
+func parsePEMKey(block *pem.Block) ([]signingKey, error) {
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type '%s'", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return []signingKey{{key: key}}, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", key)
+}
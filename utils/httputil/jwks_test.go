@@ -0,0 +1,277 @@
+package httputil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testRSAKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	testECKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	testHMACKey   = []byte("0123456789abcdef0123456789abcdef")
+)
+
+func encodeBigInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func testJWKSet() JWKSet {
+	return JWKSet{Keys: []JWK{
+		{
+			Kid: "rsa",
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   encodeBigInt(testRSAKey.N),
+			E:   encodeBigInt(big.NewInt(int64(testRSAKey.E))),
+		},
+		{
+			Kid: "ec",
+			Kty: "EC",
+			Crv: "P-256",
+			X:   encodeBigInt(testECKey.X),
+			Y:   encodeBigInt(testECKey.Y),
+		},
+		{
+			Kid: "enc",
+			Kty: "RSA",
+			Use: "enc",
+		},
+	}}
+}
+
+// newJWKSServer returns a httptest server that serves the JWK set and counts the number of requests.
+func newJWKSServer(set *JWKSet, requests *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set(ContentTypeHeaderKey, ApplicationJsonMIMEType)
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+}
+
+func TestJWKSKeyProvider(t *testing.T) {
+	set := testJWKSet()
+	var requests atomic.Int32
+	server := newJWKSServer(&set, &requests)
+	defer server.Close()
+
+	p := NewJWKSKeyProvider(server.URL, WithJWKSMinRefreshInterval(0))
+
+	t.Run("rsa", func(t *testing.T) {
+		key, err := p.Key(context.Background(), "rsa", "RS256")
+		assert.NoError(t, err)
+		assert.True(t, testRSAKey.PublicKey.Equal(key))
+	})
+
+	t.Run("ec", func(t *testing.T) {
+		key, err := p.Key(context.Background(), "ec", "ES256")
+		assert.NoError(t, err)
+		assert.True(t, testECKey.PublicKey.Equal(key))
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("algorithm mismatch", func(t *testing.T) {
+		_, err := p.Key(context.Background(), "rsa", "HS256")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown kid refreshes", func(t *testing.T) {
+		requests.Store(0)
+		_, err := p.Key(context.Background(), "unknown", "RS256")
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("min refresh interval", func(t *testing.T) {
+		p := NewJWKSKeyProvider(server.URL, WithJWKSHTTPClient(server.Client()))
+		requests.Store(0)
+		_, _ = p.Key(context.Background(), "rsa", "RS256")
+		_, err := p.Key(context.Background(), "unknown", "RS256")
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("refresh interval", func(t *testing.T) {
+		p := NewJWKSKeyProvider(server.URL, WithJWKSRefreshInterval(time.Nanosecond), WithJWKSMinRefreshInterval(0))
+		requests.Store(0)
+		_, _ = p.Key(context.Background(), "rsa", "RS256")
+		_, err := p.Key(context.Background(), "rsa", "RS256")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		_, err := NewJWKSKeyProvider(failing.URL).Key(context.Background(), "rsa", "RS256")
+		assert.ErrorContains(t, err, "unexpected status 500")
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		var down atomic.Bool
+		var requests atomic.Int32
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if down.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(set)
+		}))
+		defer flaky.Close()
+
+		p := NewJWKSKeyProvider(flaky.URL, WithJWKSRefreshInterval(time.Nanosecond))
+		_, err := p.Key(context.Background(), "rsa", "RS256")
+		assert.NoError(t, err)
+
+		// the cached keys are used while the JWKS url is down and the fetch is not retried for every request
+		down.Store(true)
+		p.attemptedAt = time.Now().Add(-time.Hour)
+		for i := 0; i < 3; i++ {
+			key, err := p.Key(context.Background(), "rsa", "RS256")
+			assert.NoError(t, err)
+			assert.True(t, testRSAKey.PublicKey.Equal(key))
+		}
+		assert.Equal(t, int32(2), requests.Load())
+
+		// a failed first fetch is not retried for every request either
+		requests.Store(0)
+		p = NewJWKSKeyProvider(flaky.URL)
+		for i := 0; i < 3; i++ {
+			_, err := p.Key(context.Background(), "rsa", "RS256")
+			assert.ErrorContains(t, err, "unexpected status 500")
+		}
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("cached keys are available during a fetch", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var slow atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if slow.Load() {
+				close(started)
+				<-release
+			}
+			_ = json.NewEncoder(w).Encode(set)
+		}))
+		defer server.Close()
+
+		p := NewJWKSKeyProvider(server.URL, WithJWKSMinRefreshInterval(0))
+		_, err := p.Key(context.Background(), "rsa", "RS256")
+		assert.NoError(t, err)
+
+		slow.Store(true)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = p.Key(context.Background(), "unknown", "RS256")
+		}()
+		<-started
+
+		key, err := p.Key(context.Background(), "rsa", "RS256")
+		assert.NoError(t, err)
+		assert.True(t, testRSAKey.PublicKey.Equal(key))
+		close(release)
+		<-done
+	})
+
+	t.Run("symmetric keys", func(t *testing.T) {
+		secrets := JWKSet{Keys: []JWK{{Kid: "hmac", Kty: "oct", K: base64.RawURLEncoding.EncodeToString(testHMACKey)}}}
+		server := newJWKSServer(&secrets, new(atomic.Int32))
+		defer server.Close()
+
+		_, err := NewJWKSKeyProvider(server.URL).Key(context.Background(), "hmac", "HS256")
+		assert.ErrorContains(t, err, "symmetric keys are not allowed")
+	})
+}
+
+func TestNewHMACKeyProvider(t *testing.T) {
+	p := NewHMACKeyProvider(testHMACKey)
+
+	key, err := p.Key(context.Background(), "any", "HS256")
+	assert.NoError(t, err)
+	assert.Equal(t, testHMACKey, key)
+
+	_, err = p.Key(context.Background(), "", "RS256")
+	assert.Error(t, err)
+}
+
+func TestNewKeyFileProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("pem", func(t *testing.T) {
+		der, err := x509.MarshalPKIXPublicKey(&testRSAKey.PublicKey)
+		assert.NoError(t, err)
+		path := filepath.Join(dir, "key.pem")
+		assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+		p, cErr := NewKeyFileProvider(path)
+		assert.Nil(t, cErr)
+		key, err := p.Key(context.Background(), "any", "RS256")
+		assert.NoError(t, err)
+		assert.True(t, testRSAKey.PublicKey.Equal(key))
+	})
+
+	t.Run("jwks", func(t *testing.T) {
+		data, err := json.Marshal(testJWKSet())
+		assert.NoError(t, err)
+		path := filepath.Join(dir, "jwks.json")
+		assert.NoError(t, os.WriteFile(path, data, 0600))
+
+		p, cErr := NewKeyFileProvider(path)
+		assert.Nil(t, cErr)
+		key, err := p.Key(context.Background(), "ec", "ES256")
+		assert.NoError(t, err)
+		assert.True(t, testECKey.PublicKey.Equal(key))
+	})
+
+	t.Run("jwks with symmetric key", func(t *testing.T) {
+		data, err := json.Marshal(JWKSet{Keys: []JWK{{Kid: "hmac", Kty: "oct", K: base64.RawURLEncoding.EncodeToString(testHMACKey)}}})
+		assert.NoError(t, err)
+		path := filepath.Join(dir, "secrets.json")
+		assert.NoError(t, os.WriteFile(path, data, 0600))
+
+		p, cErr := NewKeyFileProvider(path)
+		assert.Nil(t, cErr)
+		key, err := p.Key(context.Background(), "hmac", "HS256")
+		assert.NoError(t, err)
+		assert.Equal(t, testHMACKey, key)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		assert.NoError(t, os.WriteFile(path, []byte("invalid"), 0600))
+
+		p, cErr := NewKeyFileProvider(path)
+		assert.Nil(t, p)
+		assert.Equal(t, errors.ErrCodeInvalidSigningKey, cErr.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		p, cErr := NewKeyFileProvider(filepath.Join(dir, "missing.pem"))
+		assert.Nil(t, p)
+		assert.NotNil(t, cErr)
+	})
+}
@@ -0,0 +1,162 @@
+package httputil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClaimsContextKey = "claims"
+	BearerAuthScheme = "Bearer"
+
+	bearerTokenMissingMsg = "Bearer token is required"
+	bearerTokenInvalidMsg = "Bearer token is not valid"
+	tokenValidationErrMsg = "Bearer token validation failed: %s"
+	defaultClockSkew      = 30 * time.Second
+)
+
+var (
+	// DefaultJWTAlgorithms are the signing algorithms that are accepted by BearerAuth by default.
+	DefaultJWTAlgorithms = []string{"RS256", "ES256", "HS256"}
+)
+
+type (
+	// JWTConfig represents the configuration of the BearerAuth middleware.
+	JWTConfig struct {
+		Issuer     string
+		Audience   []string
+		ClockSkew  time.Duration
+		Algorithms []string
+	}
+
+	// JWTOption is an option that can be used to configure the BearerAuth middleware.
+	JWTOption func(config *JWTConfig)
+)
+
+// WithIssuer is a BearerAuth option that requires the iss claim to match the issuer.
+func WithIssuer(issuer string) JWTOption {
+	return func(config *JWTConfig) {
+		config.Issuer = issuer
+	}
+}
+
+// WithAudience is a BearerAuth option that requires the aud claim to contain at least one of the audiences.
+func WithAudience(audience ...string) JWTOption {
+	return func(config *JWTConfig) {
+		config.Audience = audience
+	}
+}
+
+// WithClockSkew is a BearerAuth option that defines the leeway that is allowed when validating
+// the exp, nbf and iat claims. Default clock skew is 30 seconds.
+func WithClockSkew(skew time.Duration) JWTOption {
+	return func(config *JWTConfig) {
+		config.ClockSkew = skew
+	}
+}
+
+// WithAlgorithms is a BearerAuth option that defines the accepted signing algorithms.
+// Default algorithms are DefaultJWTAlgorithms.
+func WithAlgorithms(algorithms ...string) JWTOption {
+	return func(config *JWTConfig) {
+		config.Algorithms = algorithms
+	}
+}
+
+// BearerAuth is a gin middleware that authenticates requests with a JWT bearer token in the Authorization header.
+// The signature of the token is verified with a key from the KeyProvider and the exp, nbf, iss and aud claims
+// are validated. Tokens without an exp claim are rejected. The claims of the token are stored in the gin context and can be retrieved using GetClaims.
+// The method returns an unauthorized error if the token is missing or not valid.
+func BearerAuth(keys KeyProvider, opts ...JWTOption) gin.HandlerFunc {
+	config := &JWTConfig{
+		ClockSkew:  defaultClockSkew,
+		Algorithms: DefaultJWTAlgorithms,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.ClockSkew),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+	if config.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(config.Issuer))
+	}
+	parser := jwt.NewParser(parserOpts...)
+
+	return func(ctx *gin.Context) {
+		token, ok := GetBearerTokenFromHeader(ctx)
+		if !ok {
+			AbortWithError(ctx, errors.UnauthorizedError(bearerTokenMissingMsg))
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return keys.Key(ctx.Request.Context(), kid, t.Method.Alg())
+		})
+		if err == nil {
+			err = config.validateAudience(claims)
+		}
+		if err != nil {
+			logger.Infof(tokenValidationErrMsg, err.Error())
+			AbortWithError(ctx, errors.UnauthorizedError(bearerTokenInvalidMsg))
+			return
+		}
+
+		principal := principalFromClaims(claims)
+		ctx.Set(ClaimsContextKey, claims)
+		ctx.Set(AuthUserKey, principal.Id)
+		SetPrincipal(ctx, principal)
+		logger.Info(authenticationSuccessMsg)
+		ctx.Next()
+	}
+}
+
+// validateAudience checks if the aud claim contains at least one of the configured audiences.
+func (config *JWTConfig) validateAudience(claims jwt.MapClaims) error {
+	if len(config.Audience) == 0 {
+		return nil
+	}
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return err
+	}
+	for _, a := range aud {
+		if slice.EntryExists(config.Audience, a) {
+			return nil
+		}
+	}
+	return fmt.Errorf("token has invalid audience %v", []string(aud))
+}
+
+// GetBearerTokenFromHeader gets the bearer token from the Authorization header.
+// The method returns false if the header does not contain a bearer token.
+func GetBearerTokenFromHeader(ctx *gin.Context) (string, bool) {
+	auth := strings.SplitN(ctx.GetHeader(AuthorizationHeaderKey), " ", 2)
+	if len(auth) != 2 || !strings.EqualFold(auth[0], BearerAuthScheme) || strings.TrimSpace(auth[1]) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(auth[1]), true
+}
+
+// GetClaims returns the claims that were stored in the gin context by the BearerAuth middleware.
+func GetClaims(ctx *gin.Context) (jwt.MapClaims, bool) {
+	v, ok := ctx.Get(ClaimsContextKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(jwt.MapClaims)
+	return claims, ok
+}
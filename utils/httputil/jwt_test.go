@@ -0,0 +1,216 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	bearerTokenMissingResponse = `{"errors":[{"code":"UNAUTHORIZED","status":401,"message":"Bearer token is required","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	bearerTokenInvalidResponse = `{"errors":[{"code":"UNAUTHORIZED","status":401,"message":"Bearer token is not valid","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+)
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	s, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return s
+}
+
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub": "user",
+		"iss": "https://issuer.com",
+		"aud": []string{"api", "other"},
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	}
+}
+
+func setupBearerRouter(keys KeyProvider, opts ...JWTOption) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(setMockTraceId, BearerAuth(keys, opts...))
+	r.GET("/claims", func(ctx *gin.Context) {
+		claims, ok := GetClaims(ctx)
+		if !ok {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.String(http.StatusOK, claims["sub"].(string))
+	})
+	return r
+}
+
+func TestBearerAuth(t *testing.T) {
+	set := testJWKSet()
+	var requests atomic.Int32
+	server := newJWKSServer(&set, &requests)
+	defer server.Close()
+	jwks := NewJWKSKeyProvider(server.URL)
+	opts := []JWTOption{WithIssuer("https://issuer.com"), WithAudience("api"), WithClockSkew(10 * time.Second)}
+
+	expired := validClaims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	expiredWithinSkew := validClaims()
+	expiredWithinSkew["exp"] = time.Now().Add(-5 * time.Second).Unix()
+	withoutExpiry := validClaims()
+	delete(withoutExpiry, "exp")
+	notBefore := validClaims()
+	notBefore["nbf"] = time.Now().Add(time.Minute).Unix()
+	wrongIssuer := validClaims()
+	wrongIssuer["iss"] = "https://other.com"
+	wrongAudience := validClaims()
+	wrongAudience["aud"] = "other"
+
+	tests := []struct {
+		name       string
+		keys       KeyProvider
+		auth       string
+		statusCode int
+		response   string
+	}{
+		{
+			name:       "missing",
+			keys:       jwks,
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenMissingResponse,
+		},
+		{
+			name:       "basic auth",
+			keys:       jwks,
+			auth:       "Basic YWRtaW46YWRtaW4=",
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenMissingResponse,
+		},
+		{
+			name:       "malformed",
+			keys:       jwks,
+			auth:       "Bearer invalid",
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "rs256",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, validClaims()),
+			statusCode: http.StatusOK,
+			response:   "user",
+		},
+		{
+			name:       "es256",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodES256, "ec", testECKey, validClaims()),
+			statusCode: http.StatusOK,
+			response:   "user",
+		},
+		{
+			name:       "hs256",
+			keys:       NewHMACKeyProvider(testHMACKey),
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodHS256, "", testHMACKey, validClaims()),
+			statusCode: http.StatusOK,
+			response:   "user",
+		},
+		{
+			name:       "wrong signature",
+			keys:       NewHMACKeyProvider(testHMACKey),
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodHS256, "", []byte("other"), validClaims()),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "algorithm not allowed",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS512, "rsa", testRSAKey, validClaims()),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "none algorithm",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "expired",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, expired),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "without expiry",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, withoutExpiry),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "expired within clock skew",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, expiredWithinSkew),
+			statusCode: http.StatusOK,
+			response:   "user",
+		},
+		{
+			name:       "not yet valid",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, notBefore),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "wrong issuer",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, wrongIssuer),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+		{
+			name:       "wrong audience",
+			keys:       jwks,
+			auth:       "Bearer " + signToken(t, jwt.SigningMethodRS256, "rsa", testRSAKey, wrongAudience),
+			statusCode: http.StatusUnauthorized,
+			response:   bearerTokenInvalidResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/claims", nil)
+			if tt.auth != "" {
+				req.Header.Set(AuthorizationHeaderKey, tt.auth)
+			}
+			setupBearerRouter(tt.keys, opts...).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestGetClaims(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	claims, ok := GetClaims(ctx)
+	assert.False(t, ok)
+	assert.Nil(t, claims)
+
+	ctx.Set(ClaimsContextKey, jwt.MapClaims{"sub": "user"})
+	claims, ok = GetClaims(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "user", claims["sub"])
+}
@@ -0,0 +1,106 @@
+package httputil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// TimeoutConfig represents the configuration of the Timeout middleware.
+	TimeoutConfig struct {
+		Status int
+	}
+
+	// TimeoutOption is an option that can be used to configure the Timeout middleware.
+	TimeoutOption func(config *TimeoutConfig)
+
+	// limitedBody is a request body that records if the body size limit was exceeded.
+	limitedBody struct {
+		io.ReadCloser
+		exceeded bool
+	}
+)
+
+// Read reads from the body and records if the body size limit was exceeded.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if _, ok := err.(*http.MaxBytesError); ok {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+// BodyLimit is a gin middleware that limits the size of the request body to maxBytes.
+// Requests with a larger Content-Length are rejected before the handler is called. Requests without
+// a Content-Length are rejected as soon as the handler reads past the limit, unless the handler
+// already wrote a response. In both cases a request too large error is returned.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.Request.ContentLength > maxBytes {
+			bodyTooLarge(ctx, maxBytes)
+			return
+		}
+		if ctx.Request.Body == nil || ctx.Request.Body == http.NoBody {
+			ctx.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes)}
+		ctx.Request.Body = body
+		ctx.Next()
+
+		if body.exceeded && !ctx.Writer.Written() {
+			bodyTooLarge(ctx, maxBytes)
+		}
+	}
+}
+
+// bodyTooLarge writes a request too large error.
+func bodyTooLarge(ctx *gin.Context, maxBytes int64) {
+	AbortWithError(ctx, errors.Newf(
+		errors.ErrCodeRequestTooLarge,
+		http.StatusRequestEntityTooLarge,
+		errors.ErrMsg[errors.ErrCodeRequestTooLarge],
+		maxBytes,
+	))
+}
+
+// WithTimeoutStatus is a Timeout option that defines the status of the response if the deadline is exceeded.
+// Default status is 503 Service Unavailable, 504 Gateway Timeout is the common alternative.
+func WithTimeoutStatus(status int) TimeoutOption {
+	return func(config *TimeoutConfig) {
+		config.Status = status
+	}
+}
+
+// Timeout is a gin middleware that sets a deadline of timeout on the context of the request.
+// Handlers should pass ctx.Request.Context() to downstream calls, so that they are cancelled when the
+// deadline is exceeded. If the deadline is exceeded and the handler did not write a response,
+// a request timeout error is returned.
+func Timeout(timeout time.Duration, opts ...TimeoutOption) gin.HandlerFunc {
+	config := &TimeoutConfig{Status: http.StatusServiceUnavailable}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(ctx *gin.Context) {
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+
+		ctx.Next()
+
+		if reqCtx.Err() == context.DeadlineExceeded && !ctx.Writer.Written() {
+			AbortWithError(ctx, errors.Newf(
+				errors.ErrCodeRequestTimeout,
+				config.Status,
+				errors.ErrMsg[errors.ErrCodeRequestTimeout],
+				timeout,
+			))
+		}
+	}
+}
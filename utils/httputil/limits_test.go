@@ -0,0 +1,93 @@
+package httputil
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	bodyTooLargeResponse   = `{"errors":[{"code":"REQUEST_TOO_LARGE","status":413,"message":"Request body must not exceed 8 bytes","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	requestTimeoutResponse = `{"errors":[{"code":"REQUEST_TIMEOUT","status":503,"message":"Request could not be processed within 10ms","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+)
+
+func TestBodyLimit(t *testing.T) {
+	router := setupMockRouter(setMockTraceId, BodyLimit(8))
+	router.POST("/echo", func(ctx *gin.Context) {
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return
+		}
+		ctx.String(http.StatusOK, string(body))
+	})
+
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+		statusCode    int
+		response      string
+	}{
+		{name: "within limit", body: "12345678", contentLength: 8, statusCode: http.StatusOK, response: "12345678"},
+		{name: "content length too large", body: "123456789", contentLength: 9, statusCode: http.StatusRequestEntityTooLarge, response: bodyTooLargeResponse},
+		{name: "unknown content length", body: "123456789", contentLength: -1, statusCode: http.StatusRequestEntityTooLarge, response: bodyTooLargeResponse},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/echo", io.NopCloser(strings.NewReader(tt.body)))
+			req.ContentLength = tt.contentLength
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	slowHandler := func(ctx *gin.Context) {
+		select {
+		case <-ctx.Request.Context().Done():
+		case <-time.After(time.Second):
+			ctx.String(http.StatusOK, "OK")
+		}
+	}
+
+	t.Run("within deadline", func(t *testing.T) {
+		router := setupMockRouter(setMockTraceId, Timeout(time.Second))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "OK", w.Body.String())
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		router := setupMockRouter(setMockTraceId, Timeout(10*time.Millisecond))
+		router.GET("/slow", slowHandler)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, requestTimeoutResponse, w.Body.String())
+	})
+
+	t.Run("gateway timeout", func(t *testing.T) {
+		router := setupMockRouter(setMockTraceId, Timeout(10*time.Millisecond, WithTimeoutStatus(http.StatusGatewayTimeout)))
+		router.GET("/slow", slowHandler)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+}
@@ -2,12 +2,12 @@ package httputil
 
 import (
 	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/trace"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 const (
-	TraceIDHeaderKey          = "Trace-Id"
+	TraceIDHeaderKey          = trace.HeaderKey
 	ConsumerIdHeaderKey       = "Consumer-ID"
 	SubjectTokenTypeHeaderKey = "Subject-Token-Type"
 	SubjectTokenHeaderKey     = "Subject-Token"
@@ -15,9 +15,15 @@ const (
 	authenticationSuccessMsg = "Authenticated successfully"
 )
 
+var (
+	defaultTraceId = TraceId()
+)
+
+// GenerateTraceId is a gin middleware that makes sure every request has a trace id.
+// It keeps a valid incoming Trace-Id header and only generates a new trace id if it is absent.
+// See TraceId for a configurable version of the middleware.
 func GenerateTraceId(ctx *gin.Context) {
-	ctx.Request.Header.Set(TraceIDHeaderKey, uuid.NewString())
-	ctx.Next()
+	defaultTraceId(ctx)
 }
 
 // BasicAuthRequired is a gin middleware for checking if basic authentication is provided in the request
@@ -35,26 +41,31 @@ func BasicAuthRequired() gin.HandlerFunc {
 
 // BasicAuth is a gin middleware for validation if basic authentication is provided in the request
 // and the auth user and password matches with the stored user accounts.
+// The passwords are compared in constant time.
 // The method writes the basic auth to the gin context
 // The method returns an errors if basic authentication is not set and
 // if the authentication fails to match with a user account.
 func BasicAuth(accounts map[string]string) gin.HandlerFunc {
+	return BasicAuthWithStore(PlaintextCredentials(accounts))
+}
+
+// BasicAuthWithStore is a gin middleware for validation if basic authentication is provided in the request
+// and the auth user and password are valid according to the CredentialStore.
+// The method writes the basic auth to the gin context
+// The method returns an errors if basic authentication is not set and
+// if the authentication fails.
+func BasicAuthWithStore(store CredentialStore) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		if err := GetBasicAuthFromHeader(ctx); err != nil {
 			BasicAuthError(ctx)
 			return
 		}
 
-		if val, ok := accounts[ctx.GetString(AuthUserKey)]; ok {
-			if val == ctx.GetString(AuthPassKey) {
-				logger.Info(authenticationSuccessMsg)
-			} else {
-				BasicAuthFailed(ctx)
-				return
-			}
-		} else {
+		if !store.Authenticate(ctx.Request.Context(), ctx.GetString(AuthUserKey), ctx.GetString(AuthPassKey)) {
 			BasicAuthFailed(ctx)
 			return
 		}
+		SetPrincipal(ctx, &Principal{Id: ctx.GetString(AuthUserKey)})
+		logger.Info(authenticationSuccessMsg)
 	}
 }
@@ -0,0 +1,378 @@
+package httputil
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	requestBodyField          = "body"
+	requestBodyRequiredMsg    = "request body is required"
+	invalidParameterMsgFormat = "%s : %s parameter '%s' %s"
+)
+
+type (
+	// OpenApiValidationConfig represents the configuration of the OpenApiValidation middleware.
+	OpenApiValidationConfig struct {
+		BasePath          string
+		ValidateResponses bool
+	}
+
+	// OpenApiValidationOption is an option that can be used to configure the OpenApiValidation middleware.
+	OpenApiValidationOption func(config *OpenApiValidationConfig)
+
+	// openApiValidator validates requests and responses against the operations of an OpenAPI document.
+	openApiValidator struct {
+		config *OpenApiValidationConfig
+		doc    *openapi3.T
+		routes sync.Map
+	}
+
+	// openApiRoute is the operation of a gin route with the names of the path parameters.
+	openApiRoute struct {
+		route *routers.Route
+		// params maps the names of the path parameters in the spec to the names of the gin route.
+		params map[string]string
+	}
+
+	// bufferedResponseWriter keeps the response in memory until it is validated.
+	bufferedResponseWriter struct {
+		gin.ResponseWriter
+		status  int
+		written bool
+		body    bytes.Buffer
+	}
+)
+
+// WithOpenApiBasePath is an OpenApiValidation option that defines the prefix of the gin routes that is not
+// part of the paths of the spec, for example the api base path of the router group.
+func WithOpenApiBasePath(basePath string) OpenApiValidationOption {
+	return func(config *OpenApiValidationConfig) {
+		config.BasePath = strings.TrimSuffix(basePath, "/")
+	}
+}
+
+// WithResponseValidation is an OpenApiValidation option that also validates the responses of the handlers.
+// Responses that do not match the spec are replaced by a ErrCodeInvalidResponse error.
+// The responses are kept in memory until they are validated, so the option is meant to be used in tests.
+func WithResponseValidation() OpenApiValidationOption {
+	return func(config *OpenApiValidationConfig) {
+		config.ValidateResponses = true
+	}
+}
+
+// OpenApiValidation is a gin middleware that validates the requests against an OpenAPI 3 document in JSON or YAML
+// format. The operation is matched using the gin route of the request, so the middleware has to be registered
+// on the router or the router group of the routes. The path, query and header parameters and the request body are
+// validated before the request reaches the handler. Missing parameters are returned as ErrCodeMissingMandatoryParameter
+// errors and invalid parameters and payloads as ErrCodeInvalidPayload errors, with an error per field.
+// Requests of routes that are not described in the document are not validated.
+// Security requirements are not validated, they are the responsibility of the authentication middlewares.
+// An error is returned if the document cannot be loaded or is not valid.
+func OpenApiValidation(spec []byte, opts ...OpenApiValidationOption) (gin.HandlerFunc, *errors.Error) {
+	config := new(OpenApiValidationConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, errors.Newf(errors.ErrCodeInvalidOpenApiSpec, 0, errors.ErrMsg[errors.ErrCodeInvalidOpenApiSpec], err.Error())
+	}
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, errors.Newf(errors.ErrCodeInvalidOpenApiSpec, 0, errors.ErrMsg[errors.ErrCodeInvalidOpenApiSpec], err.Error())
+	}
+
+	v := &openApiValidator{config: config, doc: doc}
+	return v.validate, nil
+}
+
+// validate validates the request and, if response validation is enabled, the response.
+func (v *openApiValidator) validate(ctx *gin.Context) {
+	route := v.route(ctx)
+	if route == nil {
+		ctx.Next()
+		return
+	}
+
+	pathParams := make(map[string]string, len(route.params))
+	for name, ginName := range route.params {
+		pathParams[name] = strings.TrimPrefix(ctx.Param(ginName), "/")
+	}
+	input := &openapi3filter.RequestValidationInput{
+		Request:    ctx.Request,
+		PathParams: pathParams,
+		Route:      route.route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+	if err := openapi3filter.ValidateRequest(ctx.Request.Context(), input); err != nil {
+		AbortWithErrors(ctx, requestErrors(err)...)
+		return
+	}
+
+	if !v.config.ValidateResponses {
+		ctx.Next()
+		return
+	}
+
+	writer := ctx.Writer
+	buffer := &bufferedResponseWriter{ResponseWriter: writer, status: http.StatusOK}
+	ctx.Writer = buffer
+	ctx.Next()
+	ctx.Writer = writer
+
+	err := openapi3filter.ValidateResponse(ctx.Request.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 buffer.status,
+		Header:                 writer.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(buffer.body.Bytes())),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+		},
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		AbortWithError(ctx, errors.Newf(errors.ErrCodeInvalidResponse, http.StatusInternalServerError,
+			errors.ErrMsg[errors.ErrCodeInvalidResponse], strings.Join(responseErrorReasons(err), ", ")))
+		return
+	}
+	writer.WriteHeader(buffer.status)
+	if buffer.body.Len() > 0 {
+		_, _ = writer.Write(buffer.body.Bytes())
+	} else {
+		writer.WriteHeaderNow()
+	}
+}
+
+// route returns the operation of the gin route of the request or nil if the route is not described in the document.
+// The routes are cached, because the gin routes are static.
+func (v *openApiValidator) route(ctx *gin.Context) *openApiRoute {
+	fullPath := ctx.FullPath()
+	if fullPath == "" || !strings.HasPrefix(fullPath, v.config.BasePath) {
+		return nil
+	}
+	key := ctx.Request.Method + " " + fullPath
+	if route, ok := v.routes.Load(key); ok {
+		return route.(*openApiRoute)
+	}
+	route := v.findRoute(ctx.Request.Method, strings.TrimPrefix(fullPath, v.config.BasePath))
+	v.routes.Store(key, route)
+	return route
+}
+
+// findRoute returns the operation of the method and the gin route, which is compared segment by segment with the
+// paths of the document. Path parameters match path parameters, regardless of their names.
+func (v *openApiValidator) findRoute(method, ginPath string) *openApiRoute {
+	ginSegments := strings.Split(ginPath, "/")
+	for path, item := range v.doc.Paths.Map() {
+		segments := strings.Split(path, "/")
+		if len(segments) != len(ginSegments) {
+			continue
+		}
+		params := make(map[string]string)
+		for i, segment := range segments {
+			ginSegment := ginSegments[i]
+			isParam := strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+			isGinParam := strings.HasPrefix(ginSegment, ":") || strings.HasPrefix(ginSegment, "*")
+			if isParam != isGinParam || (!isParam && segment != ginSegment) {
+				params = nil
+				break
+			}
+			if isParam {
+				params[strings.Trim(segment, "{}")] = ginSegment[1:]
+			}
+		}
+		if params == nil {
+			continue
+		}
+		operation := item.GetOperation(method)
+		if operation == nil {
+			// another path of the document might match the route with the method
+			continue
+		}
+		return &openApiRoute{
+			route: &routers.Route{
+				Spec:      v.doc,
+				Path:      path,
+				PathItem:  item,
+				Method:    method,
+				Operation: operation,
+			},
+			params: params,
+		}
+	}
+	return nil
+}
+
+// requestErrors converts the errors of the request validation to errors per parameter or payload field.
+func requestErrors(err error) []*errors.Error {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var errs []*errors.Error
+		for _, e := range multi {
+			errs = append(errs, requestErrors(e)...)
+		}
+		return errs
+	}
+
+	var reqErr *openapi3filter.RequestError
+	if !stderrors.As(err, &reqErr) {
+		return []*errors.Error{invalidPayloadError("", err.Error())}
+	}
+
+	if p := reqErr.Parameter; p != nil {
+		if stderrors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) {
+			e := errors.Newf(errors.ErrCodeMissingMandatoryParameter, http.StatusBadRequest,
+				errors.ErrMsg[errors.ErrCodeMissingMandatoryParameter], p.Name)
+			e.Field = p.Name
+			return []*errors.Error{e}
+		}
+		e := invalidPayloadError(p.Name, "")
+		e.Message = fmt.Sprintf(invalidParameterMsgFormat, errors.ErrMsg[errors.ErrCodeInvalidPayload], p.In, p.Name,
+			errorReason(reqErr.Err, reqErr.Reason))
+		return []*errors.Error{e}
+	}
+
+	if stderrors.Is(reqErr.Err, openapi3filter.ErrInvalidRequired) {
+		return []*errors.Error{invalidPayloadError(requestBodyField, requestBodyRequiredMsg)}
+	}
+	var schemaErrs openapi3.MultiError
+	if stderrors.As(reqErr.Err, &schemaErrs) {
+		var errs []*errors.Error
+		for _, e := range schemaErrs {
+			errs = append(errs, invalidPayloadError(schemaErrorField(e), errorReason(e, "")))
+		}
+		return errs
+	}
+	return []*errors.Error{invalidPayloadError(schemaErrorField(reqErr.Err), errorReason(reqErr.Err, reqErr.Reason))}
+}
+
+// responseErrorReasons returns the reasons of the errors of the response validation, without the values.
+func responseErrorReasons(err error) []string {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var reasons []string
+		for _, e := range multi {
+			reasons = append(reasons, responseErrorReasons(e)...)
+		}
+		return reasons
+	}
+	var respErr *openapi3filter.ResponseError
+	if !stderrors.As(err, &respErr) {
+		return []string{err.Error()}
+	}
+	var schemaErrs openapi3.MultiError
+	if stderrors.As(respErr.Err, &schemaErrs) {
+		var reasons []string
+		for _, e := range schemaErrs {
+			reasons = append(reasons, schemaErrorField(e)+": "+errorReason(e, ""))
+		}
+		return reasons
+	}
+	if respErr.Err == nil {
+		return []string{respErr.Reason}
+	}
+	return []string{schemaErrorField(respErr.Err) + ": " + errorReason(respErr.Err, respErr.Reason)}
+}
+
+// invalidPayloadError returns a ErrCodeInvalidPayload error of the field.
+func invalidPayloadError(field, reason string) *errors.Error {
+	msg := errors.ErrMsg[errors.ErrCodeInvalidPayload]
+	if reason != "" {
+		msg += " : " + reason
+	}
+	return &errors.Error{
+		Code:    errors.ErrCodeInvalidPayload,
+		Status:  http.StatusBadRequest,
+		Message: msg,
+		Field:   field,
+	}
+}
+
+// errorReason returns the reason of a schema or parse error, which does not contain the value, or the fallback.
+func errorReason(err error, fallback string) string {
+	var schemaErr *openapi3.SchemaError
+	if stderrors.As(err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	var parseErr *openapi3filter.ParseError
+	if stderrors.As(err, &parseErr) && parseErr.Reason != "" {
+		return parseErr.Reason
+	}
+	if fallback != "" {
+		return fallback
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// schemaErrorField returns the path of the payload field of a schema error, for example address.city.
+func schemaErrorField(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if stderrors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			return strings.Join(pointer, ".")
+		}
+	}
+	return requestBodyField
+}
+
+// WriteHeader keeps the status until the response is validated.
+func (w *bufferedResponseWriter) WriteHeader(code int) {
+	if code > 0 {
+		w.status = code
+		w.written = true
+	}
+}
+
+// WriteHeaderNow marks the response as written.
+func (w *bufferedResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+// Write keeps the data until the response is validated.
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+// WriteString keeps the data until the response is validated.
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+// Status returns the status of the response.
+func (w *bufferedResponseWriter) Status() int {
+	return w.status
+}
+
+// Size returns the number of bytes of the body, or -1 if nothing was written.
+func (w *bufferedResponseWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written returns true if the response was written.
+func (w *bufferedResponseWriter) Written() bool {
+	return w.written
+}
@@ -0,0 +1,211 @@
+package httputil
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testUsersSpecFile = "testdata/users.yaml"
+	testUserPayload   = `{"name":"test","address":{"city":"Amsterdam"}}`
+)
+
+func newOpenApiRouter(t *testing.T, opts ...OpenApiValidationOption) *gin.Engine {
+	spec, err := os.ReadFile(testUsersSpecFile)
+	assert.NoError(t, err)
+	validation, cErr := OpenApiValidation(spec, opts...)
+	assert.Nil(t, cErr)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	api := r.Group("/api", validation)
+	api.GET("/users", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, []gin.H{{"name": "test", "address": gin.H{"city": "Amsterdam"}}})
+	})
+	api.POST("/users", func(ctx *gin.Context) {
+		body, _ := io.ReadAll(ctx.Request.Body)
+		ctx.Data(http.StatusCreated, ApplicationJsonMIMEType, body)
+	})
+	api.GET("/users/:id", func(ctx *gin.Context) {
+		if ctx.Param("id") == "0" {
+			ctx.JSON(http.StatusOK, gin.H{"name": ""})
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"name": "test", "address": gin.H{"city": "Amsterdam"}})
+	})
+	api.GET("/health", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "OK")
+	})
+	return r
+}
+
+func serveOpenApi(r *gin.Engine, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(ContentTypeHeaderKey, ApplicationJsonMIMEType)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func readErrors(t *testing.T, w *httptest.ResponseRecorder) []errors.Error {
+	var errs errors.Errors
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &errs))
+	return errs.Errors
+}
+
+func TestOpenApiValidation(t *testing.T) {
+	r := newOpenApiRouter(t, WithOpenApiBasePath("/api"))
+
+	t.Run("valid requests", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodGet, "/api/users?limit=10", "", map[string]string{"X-Tenant": "acme"})
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = serveOpenApi(r, http.MethodPost, "/api/users", testUserPayload, nil)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, testUserPayload, w.Body.String())
+
+		w = serveOpenApi(r, http.MethodGet, "/api/users/1", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("routes that are not described", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodGet, "/api/health", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("missing parameter", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodGet, "/api/users", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs := readErrors(t, w)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errors.ErrCodeMissingMandatoryParameter, errs[0].Code)
+		assert.Equal(t, "limit", errs[0].Field)
+		assert.Equal(t, "Missing mandatory parameters : limit", errs[0].Message)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodGet, "/api/users?limit=1000", "", map[string]string{"X-Tenant": "initech"})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs := readErrors(t, w)
+		assert.Len(t, errs, 2)
+		fields := []string{errs[0].Field, errs[1].Field}
+		assert.ElementsMatch(t, []string{"limit", "X-Tenant"}, fields)
+		for _, err := range errs {
+			assert.Equal(t, errors.ErrCodeInvalidPayload, err.Code)
+			assert.NotContains(t, err.Message, "initech")
+		}
+
+		w = serveOpenApi(r, http.MethodGet, "/api/users/abc", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs = readErrors(t, w)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, "userId", errs[0].Field)
+		assert.True(t, strings.HasPrefix(errs[0].Message, "Payload is not valid : path parameter 'userId'"))
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodPost, "/api/users", `{"name":"","address":{}}`, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs := readErrors(t, w)
+		assert.Len(t, errs, 2)
+		fields := []string{errs[0].Field, errs[1].Field}
+		assert.ElementsMatch(t, []string{"name", "address.city"}, fields)
+		for _, err := range errs {
+			assert.Equal(t, errors.ErrCodeInvalidPayload, err.Code)
+			assert.True(t, strings.HasPrefix(err.Message, "Payload is not valid : "))
+		}
+
+		w = serveOpenApi(r, http.MethodPost, "/api/users", `{"name":`, nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs = readErrors(t, w)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errors.ErrCodeInvalidPayload, errs[0].Code)
+		assert.Equal(t, requestBodyField, errs[0].Field)
+	})
+
+	t.Run("missing payload", func(t *testing.T) {
+		w := serveOpenApi(r, http.MethodPost, "/api/users", "", nil)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		errs := readErrors(t, w)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, errors.ErrCodeInvalidPayload, errs[0].Code)
+		assert.Equal(t, requestBodyField, errs[0].Field)
+		assert.Equal(t, "Payload is not valid : "+requestBodyRequiredMsg, errs[0].Message)
+	})
+}
+
+func TestOpenApiValidationResponses(t *testing.T) {
+	r := newOpenApiRouter(t, WithOpenApiBasePath("/api/"), WithResponseValidation())
+
+	w := serveOpenApi(r, http.MethodGet, "/api/users/1", "", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, testUserPayload, w.Body.String())
+
+	w = serveOpenApi(r, http.MethodPost, "/api/users", testUserPayload, nil)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, testUserPayload, w.Body.String())
+
+	w = serveOpenApi(r, http.MethodGet, "/api/users/0", "", nil)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	errs := readErrors(t, w)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, errors.ErrCodeInvalidResponse, errs[0].Code)
+	assert.Equal(t, `Response does not match the API specification : name: minimum string length is 1, `+
+		`address: property "address" is missing`, errs[0].Message)
+
+	// invalid requests are not passed to the handler
+	w = serveOpenApi(r, http.MethodGet, "/api/users", "", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOpenApiValidator_FindRoute(t *testing.T) {
+	// the paths have the same segments, only one of them has the method of the route
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: test, version: "1"}
+paths:
+  /items/{id}:
+    get:
+      responses: {"200": {description: ok}}
+  /items/{itemId}:
+    delete:
+      responses: {"204": {description: deleted}}
+`))
+	assert.NoError(t, err)
+	v := &openApiValidator{config: new(OpenApiValidationConfig), doc: doc}
+
+	// the paths are iterated in random order
+	for i := 0; i < 20; i++ {
+		route := v.findRoute(http.MethodDelete, "/items/:id")
+		if assert.NotNil(t, route) {
+			assert.Equal(t, "/items/{itemId}", route.route.Path)
+			assert.Equal(t, map[string]string{"itemId": "id"}, route.params)
+		}
+	}
+	assert.Nil(t, v.findRoute(http.MethodPost, "/items/:id"))
+}
+
+func TestOpenApiValidationInvalidSpec(t *testing.T) {
+	_, err := OpenApiValidation([]byte("openapi: [3.0"))
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrCodeInvalidOpenApiSpec, err.Code)
+
+	_, err = OpenApiValidation([]byte(`{"openapi":"3.0.3","info":{"title":"test"},"paths":{}}`))
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.ErrCodeInvalidOpenApiSpec, err.Code)
+}
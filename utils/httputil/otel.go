@@ -0,0 +1,216 @@
+package httputil
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/atselvan/go-utils/utils/httputil"
+
+	retriedAttemptMsg = "Attempt failed and was retried"
+)
+
+var (
+	// traceIdHeaderAttributeKey is the span attribute of the Trace-Id header.
+	traceIdHeaderAttributeKey = attribute.Key("http.request.header.trace-id")
+)
+
+type (
+	// TracingConfig represents the configuration of the Tracing middleware and the TraceResty hooks.
+	TracingConfig struct {
+		TracerProvider trace.TracerProvider
+		Propagator     propagation.TextMapPropagator
+		SkipPaths      []string
+	}
+
+	// TracingOption is an option that can be used to configure the Tracing middleware and the TraceResty hooks.
+	TracingOption func(config *TracingConfig)
+
+	// restyParentCtxKey is the key of the parent context of the client spans of a resty request.
+	restyParentCtxKey struct{}
+)
+
+// WithTracerProvider is a tracing option that defines the tracer provider. Default is otel.GetTracerProvider().
+func WithTracerProvider(provider trace.TracerProvider) TracingOption {
+	return func(config *TracingConfig) {
+		config.TracerProvider = provider
+	}
+}
+
+// WithPropagator is a tracing option that defines the propagator. Default is otel.GetTextMapPropagator().
+func WithPropagator(propagator propagation.TextMapPropagator) TracingOption {
+	return func(config *TracingConfig) {
+		config.Propagator = propagator
+	}
+}
+
+// WithTracingSkipPaths is a Tracing option that defines paths for which no spans are created.
+func WithTracingSkipPaths(paths ...string) TracingOption {
+	return func(config *TracingConfig) {
+		config.SkipPaths = paths
+	}
+}
+
+// newTracingConfig returns the tracing configuration with the options applied.
+func newTracingConfig(opts []TracingOption) *TracingConfig {
+	config := new(TracingConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+	if config.Propagator == nil {
+		config.Propagator = otel.GetTextMapPropagator()
+	}
+	return config
+}
+
+// Tracing is a gin middleware that starts an OpenTelemetry server span per request.
+// The W3C trace context of the request is extracted with the propagator and the span is stored in the
+// request context. If the request has no Trace-Id header, the trace id of the span is set as the Trace-Id header,
+// so that it is used by the TraceId middleware and in the logs. Tracing should therefore be registered
+// before the TraceId middleware.
+// The status of the response is recorded on the span and an error that was written by AbortWithError is
+// recorded as an exception event. Responses with a 5xx status mark the span as failed.
+func Tracing(opts ...TracingOption) gin.HandlerFunc {
+	config := newTracingConfig(opts)
+	tracer := config.TracerProvider.Tracer(tracerName)
+
+	return func(ctx *gin.Context) {
+		if slice.EntryExists(config.SkipPaths, ctx.Request.URL.Path) {
+			ctx.Next()
+			return
+		}
+
+		parent := config.Propagator.Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+		spanCtx, span := tracer.Start(parent, spanName(ctx.Request.Method, ctx.FullPath()),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.URLPath(ctx.Request.URL.Path),
+				semconv.ClientAddress(ctx.ClientIP()),
+				semconv.UserAgentOriginal(ctx.Request.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		if ctx.GetHeader(TraceIDHeaderKey) == "" && span.SpanContext().HasTraceID() {
+			ctx.Request.Header.Set(TraceIDHeaderKey, span.SpanContext().TraceID().String())
+		}
+		ctx.Request = ctx.Request.WithContext(spanCtx)
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(status),
+			traceIdHeaderAttributeKey.String(GetTraceId(ctx)),
+		)
+		if route := ctx.FullPath(); route != "" {
+			span.SetName(spanName(ctx.Request.Method, route))
+			span.SetAttributes(semconv.HTTPRoute(route))
+		}
+		if err, ok := GetError(ctx); ok {
+			span.SetAttributes(semconv.ErrorTypeKey.String(err.Code))
+			span.RecordError(fmt.Errorf("%s", err.Message))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// TraceResty attaches hooks to the resty client that start an OpenTelemetry client span per attempt of a request.
+// The span is a child of the span in the context of the request, see resty.Request.SetContext.
+// The trace context is injected in the request headers with the propagator and the Trace-Id header is set to
+// the trace id of the request context or, if it is not set, to the trace id of the span.
+// Responses with a 4xx or 5xx status and requests that failed without a response mark the span as failed.
+func TraceResty(client *resty.Client, opts ...TracingOption) *resty.Client {
+	config := newTracingConfig(opts)
+	tracer := config.TracerProvider.Tracer(tracerName)
+
+	return client.
+		OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			parent := req.Context()
+			if p, ok := parent.Value(restyParentCtxKey{}).(context.Context); ok {
+				// a retry, the span of the previous attempt must not be the parent and is ended
+				// if the attempt failed without a response
+				if previous := trace.SpanFromContext(parent); previous.IsRecording() {
+					previous.SetStatus(codes.Error, retriedAttemptMsg)
+					previous.End()
+				}
+				parent = p
+			}
+			attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(req.Method)}
+			if u, err := url.Parse(req.URL); err == nil {
+				// the query and user info are not recorded, because they might contain credentials
+				u.RawQuery = ""
+				u.User = nil
+				attrs = append(attrs, semconv.URLFull(u.String()), semconv.ServerAddress(u.Hostname()))
+			}
+			spanCtx, span := tracer.Start(parent, spanName(req.Method, ""),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			config.Propagator.Inject(spanCtx, propagation.HeaderCarrier(req.Header))
+			if req.Header.Get(TraceIDHeaderKey) == "" {
+				if traceId := TraceIdFromContext(parent); traceId != "" {
+					req.Header.Set(TraceIDHeaderKey, traceId)
+				} else if span.SpanContext().HasTraceID() {
+					req.Header.Set(TraceIDHeaderKey, span.SpanContext().TraceID().String())
+				}
+			}
+			req.SetContext(context.WithValue(spanCtx, restyParentCtxKey{}, parent))
+			return nil
+		}).
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			if resp.Request.Context().Value(restyParentCtxKey{}) == nil {
+				return nil
+			}
+			span := trace.SpanFromContext(resp.Request.Context())
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode()))
+			if resp.StatusCode() >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode()))
+			}
+			span.End()
+			return nil
+		}).
+		OnError(func(req *resty.Request, err error) {
+			var respErr *resty.ResponseError
+			if stderrors.As(err, &respErr) && respErr.Response != nil && respErr.Response.RawResponse != nil {
+				// the call received a response and the span was ended by the response hook
+				return
+			}
+			if req.Context().Value(restyParentCtxKey{}) == nil {
+				// the request failed before a span was started
+				return
+			}
+			span := trace.SpanFromContext(req.Context())
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+		})
+}
+
+// spanName returns the name of a span, which is the method followed by the route if it is known.
+func spanName(method, route string) string {
+	if route == "" {
+		return method
+	}
+	return method + " " + route
+}
@@ -0,0 +1,233 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	mockTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	mockOtelTraceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	mockTraceId     = "967ed3d6-33ce-4091-943d-b3a6f8b591be"
+)
+
+func newMockTracing() (*tracetest.InMemoryExporter, []TracingOption) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return exporter, []TracingOption{WithTracerProvider(provider), WithPropagator(propagation.TraceContext{})}
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("server span", func(t *testing.T) {
+		exporter, opts := newMockTracing()
+		r := gin.New()
+		r.Use(Tracing(opts...), TraceId())
+		var spanCtx trace.SpanContext
+		r.GET("/users/:id", func(ctx *gin.Context) {
+			spanCtx = trace.SpanContextFromContext(ctx.Request.Context())
+			ctx.String(http.StatusOK, "OK")
+		})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+		req.Header.Set(TraceParentHeaderKey, mockTraceParent)
+		r.ServeHTTP(w, req)
+
+		spans := exporter.GetSpans()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "GET /users/:id", spans[0].Name)
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+		assert.Equal(t, mockOtelTraceId, spans[0].SpanContext.TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+		assert.Equal(t, spans[0].SpanContext.SpanID(), spanCtx.SpanID())
+		assert.Equal(t, "/users/:id", spanAttribute(spans[0], "http.route").AsString())
+		assert.Equal(t, int64(http.StatusOK), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+		// the trace id of the span is bridged into the Trace-Id header
+		assert.Equal(t, mockOtelTraceId, w.Header().Get(TraceIDHeaderKey))
+		assert.Equal(t, mockOtelTraceId, spanAttribute(spans[0], traceIdHeaderAttributeKey).AsString())
+	})
+
+	t.Run("incoming trace id is kept", func(t *testing.T) {
+		exporter, opts := newMockTracing()
+		r := gin.New()
+		r.Use(Tracing(opts...), TraceId())
+		r.GET("/login", func(ctx *gin.Context) { ctx.String(http.StatusOK, "OK") })
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+		req.Header.Set(TraceIDHeaderKey, mockTraceId)
+		r.ServeHTTP(w, req)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, mockTraceId, w.Header().Get(TraceIDHeaderKey))
+		assert.Equal(t, mockTraceId, spanAttribute(spans[0], traceIdHeaderAttributeKey).AsString())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		exporter, opts := newMockTracing()
+		r := gin.New()
+		r.Use(Tracing(opts...))
+		r.GET("/error", func(ctx *gin.Context) {
+			AbortWithError(ctx, errors.InternalServerError("something went wrong"))
+		})
+		r.GET("/forbidden", func(ctx *gin.Context) {
+			AbortWithError(ctx, errors.ForbiddenError("not allowed"))
+		})
+
+		for _, path := range []string{"/error", "/forbidden"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			r.ServeHTTP(w, req)
+		}
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, errors.ErrCodeInternalServerError, spanAttribute(spans[0], "error.type").AsString())
+		assert.Len(t, spans[0].Events, 1)
+		assert.Equal(t, "exception", spans[0].Events[0].Name)
+
+		// client errors are recorded but do not mark the server span as failed
+		assert.Equal(t, codes.Unset, spans[1].Status.Code)
+		assert.Equal(t, errors.ErrCodeInsufficientAccess, spanAttribute(spans[1], "error.type").AsString())
+		assert.Len(t, spans[1].Events, 1)
+	})
+
+	t.Run("unmatched route and skip paths", func(t *testing.T) {
+		exporter, opts := newMockTracing()
+		r := gin.New()
+		r.Use(Tracing(append(opts, WithTracingSkipPaths(healthApiPath))...))
+		r.GET(healthApiPath, func(ctx *gin.Context) { ctx.String(http.StatusOK, "OK") })
+
+		for _, path := range []string{healthApiPath, notFoundApiPath} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			r.ServeHTTP(w, req)
+		}
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, http.MethodGet, spans[0].Name)
+		assert.Equal(t, int64(http.StatusNotFound), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+	})
+}
+
+func TestTraceResty(t *testing.T) {
+	exporter, opts := newMockTracing()
+	client := TraceResty(resty.New(), opts...)
+	httpmock.ActivateNonDefault(client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	var headers http.Header
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/ok", func(req *http.Request) (*http.Response, error) {
+		headers = req.Header.Clone()
+		return httpmock.NewStringResponse(http.StatusOK, "OK"), nil
+	})
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/error", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/failure", httpmock.NewErrorResponder(fmt.Errorf("connection refused")))
+
+	t.Run("client span", func(t *testing.T) {
+		exporter.Reset()
+		tracer := newTracingConfig(opts).TracerProvider.Tracer("test")
+		parentCtx, parent := tracer.Start(ContextWithTraceId(context.Background(), mockTraceId), "parent")
+
+		_, err := client.R().SetContext(parentCtx).SetQueryParam("api_key", "secret").Get("https://test.com/ok")
+		parent.End()
+
+		spans := exporter.GetSpans()
+		assert.Nil(t, err)
+		assert.Len(t, spans, 2)
+		assert.Equal(t, http.MethodGet, spans[0].Name)
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+		assert.Equal(t, "https://test.com/ok", spanAttribute(spans[0], "url.full").AsString())
+		assert.Equal(t, "test.com", spanAttribute(spans[0], "server.address").AsString())
+		assert.Equal(t, int64(http.StatusOK), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Equal(t, fmt.Sprintf("00-%s-%s-01", spans[0].SpanContext.TraceID(), spans[0].SpanContext.SpanID()),
+			headers.Get(TraceParentHeaderKey))
+		assert.Equal(t, mockTraceId, headers.Get(TraceIDHeaderKey))
+	})
+
+	t.Run("trace id from span", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := client.R().Get("https://test.com/ok")
+
+		spans := exporter.GetSpans()
+		assert.Nil(t, err)
+		assert.Len(t, spans, 1)
+		assert.False(t, spans[0].Parent.IsValid())
+		assert.Equal(t, spans[0].SpanContext.TraceID().String(), headers.Get(TraceIDHeaderKey))
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		exporter.Reset()
+
+		_, _ = client.R().Get("https://test.com/error")
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Equal(t, int64(http.StatusInternalServerError), spanAttribute(spans[0], "http.response.status_code").AsInt64())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		exporter.Reset()
+
+		_, err := client.R().Get("https://test.com/failure")
+
+		spans := exporter.GetSpans()
+		assert.NotNil(t, err)
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Len(t, spans[0].Events, 1)
+		assert.Equal(t, "exception", spans[0].Events[0].Name)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		exporter.Reset()
+		client.SetRetryCount(2)
+		defer client.SetRetryCount(0)
+
+		_, err := client.R().Get("https://test.com/failure")
+
+		spans := exporter.GetSpans()
+		assert.NotNil(t, err)
+		assert.Len(t, spans, 3)
+		for _, span := range spans {
+			assert.Equal(t, codes.Error, span.Status.Code)
+			assert.False(t, span.Parent.IsValid())
+		}
+	})
+}
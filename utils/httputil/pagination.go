@@ -0,0 +1,389 @@
+package httputil
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	PageParam       = "page"
+	SizeParam       = "size"
+	CursorParam     = "cursor"
+	SortParam       = "sort"
+	LinkHeaderKey   = "Link"
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	sortDescPrefix = "-"
+
+	invalidPositiveIntMsg  = "Query parameter '%s' must be a positive integer"
+	pageSizeExceededMsg    = "Query parameter '%s' must not exceed %d"
+	invalidCursorMsg       = "Query parameter '%s' is not a valid cursor"
+	sortNotSupportedMsg    = "Sorting by '%s' is not supported"
+	filterNotSupportedMsg  = "Filtering by '%s' is not supported"
+	operatorNotAllowedMsg  = "Filter operator '%s' is not supported for '%s'"
+	linkHeaderFormat       = `<%s>; rel="%s"`
+	linkRelNext            = "next"
+	linkRelPrev            = "prev"
+	filterOperatorSplitter = "["
+)
+
+const (
+	FilterEq   FilterOperator = "eq"
+	FilterNe   FilterOperator = "ne"
+	FilterGt   FilterOperator = "gt"
+	FilterGte  FilterOperator = "gte"
+	FilterLt   FilterOperator = "lt"
+	FilterLte  FilterOperator = "lte"
+	FilterLike FilterOperator = "like"
+	FilterIn   FilterOperator = "in"
+)
+
+type (
+	// FilterOperator is the operator of a filter.
+	FilterOperator string
+
+	// SortField is a field the list is sorted by.
+	SortField struct {
+		Field string
+		Desc  bool
+	}
+
+	// Filter is a filter of the list, which is defined as field=value or field[operator]=value in the query.
+	Filter struct {
+		Field    string
+		Operator FilterOperator
+		Value    string
+	}
+
+	// ListQuery represents the pagination, sorting and filtering parameters of a list request.
+	ListQuery struct {
+		Page    int
+		Size    int
+		Cursor  string
+		Sort    []SortField
+		Filters []Filter
+	}
+
+	// ListConfig represents the configuration of ParseListQuery.
+	ListConfig struct {
+		DefaultSize  int
+		MaxSize      int
+		Cursor       bool
+		SortFields   []string
+		DefaultSort  []SortField
+		FilterFields map[string][]FilterOperator
+	}
+
+	// ListOption is an option that can be used to configure ParseListQuery.
+	ListOption func(config *ListConfig)
+
+	// Page is the response envelope of a list endpoint.
+	// Page, TotalItems and TotalPages are set for offset pagination, NextCursor and PrevCursor for cursor pagination.
+	Page[T any] struct {
+		Items      []T    `json:"items"`
+		Page       int    `json:"page,omitempty"`
+		Size       int    `json:"size"`
+		TotalItems int64  `json:"totalItems,omitempty"`
+		TotalPages int    `json:"totalPages,omitempty"`
+		NextCursor string `json:"nextCursor,omitempty"`
+		PrevCursor string `json:"prevCursor,omitempty"`
+	}
+)
+
+// WithPageSize is a ParseListQuery option that defines the default and the maximum page size.
+// Defaults are DefaultPageSize and MaxPageSize.
+func WithPageSize(defaultSize, maxSize int) ListOption {
+	return func(config *ListConfig) {
+		config.DefaultSize = defaultSize
+		config.MaxSize = maxSize
+	}
+}
+
+// WithCursor is a ParseListQuery option that enables cursor pagination instead of offset pagination.
+func WithCursor() ListOption {
+	return func(config *ListConfig) {
+		config.Cursor = true
+	}
+}
+
+// WithSortFields is a ParseListQuery option that defines the fields the list can be sorted by.
+func WithSortFields(fields ...string) ListOption {
+	return func(config *ListConfig) {
+		config.SortFields = fields
+	}
+}
+
+// WithDefaultSort is a ParseListQuery option that defines the sorting if the request does not have a sort parameter,
+// in the format of the sort parameter, for example name,-createdAt.
+func WithDefaultSort(sort string) ListOption {
+	return func(config *ListConfig) {
+		config.DefaultSort = parseSort([]string{sort})
+	}
+}
+
+// WithFilterField is a ParseListQuery option that allows to filter by the field with the operators.
+// If no operators are defined, only FilterEq is allowed.
+func WithFilterField(field string, operators ...FilterOperator) ListOption {
+	return func(config *ListConfig) {
+		if config.FilterFields == nil {
+			config.FilterFields = make(map[string][]FilterOperator)
+		}
+		if len(operators) == 0 {
+			operators = []FilterOperator{FilterEq}
+		}
+		config.FilterFields[field] = operators
+	}
+}
+
+// ParseListQuery parses the pagination, sorting and filtering parameters of the request.
+// Offset pagination uses the page and size parameters, cursor pagination the cursor and size parameters.
+// The list is sorted by the fields of the sort parameter, for example sort=name,-createdAt sorts by name ascending
+// and by createdAt descending. Filters are defined as field=value or field[operator]=value, for example
+// status=active&age[gte]=18; the values of FilterIn are comma separated. Only the fields of WithSortFields and
+// WithFilterField are accepted, other query parameters are ignored unless they use the operator syntax.
+// A bad request error is returned for invalid values.
+func ParseListQuery(ctx *gin.Context, opts ...ListOption) (*ListQuery, *errors.Error) {
+	config := &ListConfig{DefaultSize: DefaultPageSize, MaxSize: MaxPageSize}
+	for _, opt := range opts {
+		opt(config)
+	}
+	query := ctx.Request.URL.Query()
+	list := &ListQuery{Size: config.DefaultSize, Sort: config.DefaultSort}
+
+	if v := query.Get(SizeParam); v != "" {
+		size, err := positiveInt(SizeParam, v)
+		if err != nil {
+			return nil, err
+		}
+		if config.MaxSize > 0 && size > config.MaxSize {
+			return nil, queryParamError(SizeParam, fmt.Sprintf(pageSizeExceededMsg, SizeParam, config.MaxSize))
+		}
+		list.Size = size
+	}
+	if config.Cursor {
+		list.Cursor = query.Get(CursorParam)
+	} else {
+		list.Page = 1
+		if v := query.Get(PageParam); v != "" {
+			page, err := positiveInt(PageParam, v)
+			if err != nil {
+				return nil, err
+			}
+			list.Page = page
+		}
+	}
+
+	if values, ok := query[SortParam]; ok {
+		list.Sort = parseSort(values)
+		for _, s := range list.Sort {
+			if !slice.EntryExists(config.SortFields, s.Field) {
+				return nil, queryParamError(SortParam, fmt.Sprintf(sortNotSupportedMsg, s.Field))
+			}
+		}
+	}
+
+	filters, err := parseFilters(query, config.FilterFields)
+	if err != nil {
+		return nil, err
+	}
+	list.Filters = filters
+	return list, nil
+}
+
+// Offset returns the number of items that are skipped for offset pagination.
+func (q *ListQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Size
+}
+
+// Filter returns the filters of the field.
+func (q *ListQuery) Filter(field string) []Filter {
+	var filters []Filter
+	for _, f := range q.Filters {
+		if f.Field == field {
+			filters = append(filters, f)
+		}
+	}
+	return filters
+}
+
+// Values returns the comma separated values of a FilterIn filter or the value of other filters.
+func (f Filter) Values() []string {
+	if f.Operator != FilterIn {
+		return []string{f.Value}
+	}
+	return strings.Split(f.Value, ",")
+}
+
+// NewPage returns the envelope of a page of items for offset pagination.
+func NewPage[T any](items []T, query *ListQuery, totalItems int64) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := &Page[T]{Items: items, Page: query.Page, Size: query.Size, TotalItems: totalItems}
+	if query.Size > 0 {
+		page.TotalPages = int((totalItems + int64(query.Size) - 1) / int64(query.Size))
+	}
+	return page
+}
+
+// NewCursorPage returns the envelope of a page of items for cursor pagination.
+// The cursors are empty if there is no next or previous page.
+func NewCursorPage[T any](items []T, query *ListQuery, nextCursor, prevCursor string) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{Items: items, Size: query.Size, NextCursor: nextCursor, PrevCursor: prevCursor}
+}
+
+// WritePage writes the page with a Link header that contains the urls of the next and the previous page.
+// The urls are built from the server url, see GetServerURL, the path and the query of the request.
+// If the server configuration is nil, the urls are relative.
+func WritePage[T any](ctx *gin.Context, sc *config.ServerConfig, page *Page[T]) {
+	var links []string
+	if next := pageLink(ctx, sc, page, true); next != "" {
+		links = append(links, fmt.Sprintf(linkHeaderFormat, next, linkRelNext))
+	}
+	if prev := pageLink(ctx, sc, page, false); prev != "" {
+		links = append(links, fmt.Sprintf(linkHeaderFormat, prev, linkRelPrev))
+	}
+	if len(links) > 0 {
+		ctx.Header(LinkHeaderKey, strings.Join(links, ", "))
+	}
+	ctx.JSON(http.StatusOK, page)
+}
+
+// EncodeCursor encodes a value, for example the sort key of the last item of a page, as an opaque cursor.
+func EncodeCursor(v any) (string, *errors.Error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", errors.Newf(errors.ErrCodeJSONMarshalError, 0, errors.ErrMsg[errors.ErrCodeJSONMarshalError], err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor that was encoded with EncodeCursor into out.
+// A bad request error is returned if the cursor is not valid.
+func DecodeCursor(cursor string, out any) *errors.Error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, out)
+	}
+	if err != nil {
+		return queryParamError(CursorParam, fmt.Sprintf(invalidCursorMsg, CursorParam))
+	}
+	return nil
+}
+
+// pageLink returns the url of the next or the previous page, or an empty string if there is no such page.
+func pageLink[T any](ctx *gin.Context, sc *config.ServerConfig, page *Page[T], next bool) string {
+	query := ctx.Request.URL.Query()
+	switch {
+	case page.Page > 0 && next && page.Page < page.TotalPages:
+		query.Set(PageParam, strconv.Itoa(page.Page+1))
+	case page.Page > 1 && !next:
+		query.Set(PageParam, strconv.Itoa(min(page.Page-1, max(page.TotalPages, 1))))
+	case page.Page == 0 && next && page.NextCursor != "":
+		query.Set(CursorParam, page.NextCursor)
+	case page.Page == 0 && !next && page.PrevCursor != "":
+		query.Set(CursorParam, page.PrevCursor)
+	default:
+		return ""
+	}
+	query.Set(SizeParam, strconv.Itoa(page.Size))
+
+	u := &url.URL{Path: ctx.Request.URL.Path}
+	if sc != nil {
+		u = GetServerURL(sc, ctx.Request.URL.Path)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// parseSort parses the values of sort parameters.
+func parseSort(values []string) []SortField {
+	var sort []SortField
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			desc := strings.HasPrefix(field, sortDescPrefix)
+			sort = append(sort, SortField{Field: strings.TrimPrefix(field, sortDescPrefix), Desc: desc})
+		}
+	}
+	return sort
+}
+
+// parseFilters parses the filters of the allowed fields. The query parameters are parsed in the order of their keys,
+// so that the filters and the reported error do not depend on the order of the map.
+func parseFilters(query url.Values, fields map[string][]FilterOperator) ([]Filter, *errors.Error) {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []Filter
+	for _, key := range keys {
+		values := query[key]
+		field, operator := key, FilterEq
+		if name, op, ok := strings.Cut(key, filterOperatorSplitter); ok && strings.HasSuffix(op, "]") {
+			field, operator = name, FilterOperator(strings.TrimSuffix(op, "]"))
+		}
+		allowed, ok := fields[field]
+		if !ok {
+			if field != key {
+				return nil, queryParamError(key, fmt.Sprintf(filterNotSupportedMsg, field))
+			}
+			continue
+		}
+		if !slice.EntryExists(operatorNames(allowed), string(operator)) {
+			return nil, queryParamError(key, fmt.Sprintf(operatorNotAllowedMsg, operator, field))
+		}
+		for _, value := range values {
+			filters = append(filters, Filter{Field: field, Operator: operator, Value: value})
+		}
+	}
+	return filters, nil
+}
+
+// operatorNames returns the names of the operators.
+func operatorNames(operators []FilterOperator) []string {
+	names := make([]string, 0, len(operators))
+	for _, operator := range operators {
+		names = append(names, string(operator))
+	}
+	return names
+}
+
+// positiveInt parses the value of the query parameter as a positive integer.
+func positiveInt(param, value string) (int, *errors.Error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, queryParamError(param, fmt.Sprintf(invalidPositiveIntMsg, param))
+	}
+	return n, nil
+}
+
+// queryParamError returns a bad request error of the query parameter.
+func queryParamError(param, message string) *errors.Error {
+	err := errors.BadRequestError(message)
+	err.Field = param
+	return err
+}
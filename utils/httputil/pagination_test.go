@@ -0,0 +1,177 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newListContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, target, nil)
+	return ctx, w
+}
+
+func TestParseListQuery(t *testing.T) {
+	opts := []ListOption{
+		WithPageSize(10, 50),
+		WithSortFields("name", "createdAt"),
+		WithDefaultSort("-createdAt"),
+		WithFilterField("status"),
+		WithFilterField("age", FilterGte, FilterLte),
+		WithFilterField("role", FilterIn),
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		ctx, _ := newListContext("/users")
+		q, err := ParseListQuery(ctx, opts...)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, q.Page)
+		assert.Equal(t, 10, q.Size)
+		assert.Equal(t, 0, q.Offset())
+		assert.Equal(t, []SortField{{Field: "createdAt", Desc: true}}, q.Sort)
+		assert.Empty(t, q.Filters)
+
+		q, err = ParseListQuery(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, DefaultPageSize, q.Size)
+		assert.Nil(t, q.Sort)
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		ctx, _ := newListContext("/users?page=3&size=25&sort=name,-createdAt&status=active&age[gte]=18&role[in]=admin,user&q=ignored")
+		q, err := ParseListQuery(ctx, opts...)
+		assert.Nil(t, err)
+		assert.Equal(t, 3, q.Page)
+		assert.Equal(t, 25, q.Size)
+		assert.Equal(t, 50, q.Offset())
+		assert.Equal(t, []SortField{{Field: "name"}, {Field: "createdAt", Desc: true}}, q.Sort)
+		assert.Len(t, q.Filters, 3)
+		assert.Equal(t, []Filter{{Field: "status", Operator: FilterEq, Value: "active"}}, q.Filter("status"))
+		assert.Equal(t, []Filter{{Field: "age", Operator: FilterGte, Value: "18"}}, q.Filter("age"))
+		assert.Equal(t, []string{"admin", "user"}, q.Filter("role")[0].Values())
+		assert.Equal(t, []string{"18"}, q.Filter("age")[0].Values())
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		ctx, _ := newListContext("/users?cursor=abc&page=2")
+		q, err := ParseListQuery(ctx, append(opts, WithCursor())...)
+		assert.Nil(t, err)
+		assert.Equal(t, "abc", q.Cursor)
+		assert.Equal(t, 0, q.Page)
+		assert.Equal(t, 0, q.Offset())
+	})
+
+	t.Run("order of the filters", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			ctx, _ := newListContext("/users?status=active&age[lte]=65&age[gte]=18&role[in]=admin")
+			q, err := ParseListQuery(ctx, opts...)
+			assert.Nil(t, err)
+			assert.Equal(t, []Filter{
+				{Field: "age", Operator: FilterGte, Value: "18"},
+				{Field: "age", Operator: FilterLte, Value: "65"},
+				{Field: "role", Operator: FilterIn, Value: "admin"},
+				{Field: "status", Operator: FilterEq, Value: "active"},
+			}, q.Filters)
+
+			ctx, _ = newListContext("/users?zip[eq]=1&email[eq]=a&age[gt]=18")
+			_, err = ParseListQuery(ctx, opts...)
+			assert.Equal(t, "age[gt]", err.Field)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		tests := map[string]struct {
+			field   string
+			message string
+		}{
+			"/users?page=0":         {PageParam, "Query parameter 'page' must be a positive integer"},
+			"/users?size=abc":       {SizeParam, "Query parameter 'size' must be a positive integer"},
+			"/users?size=51":        {SizeParam, "Query parameter 'size' must not exceed 50"},
+			"/users?sort=password":  {SortParam, "Sorting by 'password' is not supported"},
+			"/users?sort=name,-age": {SortParam, "Sorting by 'age' is not supported"},
+			"/users?email[eq]=a":    {"email[eq]", "Filtering by 'email' is not supported"},
+			"/users?age[gt]=18":     {"age[gt]", "Filter operator 'gt' is not supported for 'age'"},
+			"/users?age=18":         {"age", "Filter operator 'eq' is not supported for 'age'"},
+		}
+		for target, expected := range tests {
+			ctx, _ := newListContext(target)
+			_, err := ParseListQuery(ctx, opts...)
+			assert.NotNil(t, err, target)
+			assert.Equal(t, errors.ErrCodeBadRequest, err.Code)
+			assert.Equal(t, http.StatusBadRequest, err.Status)
+			assert.Equal(t, expected.field, err.Field)
+			assert.Equal(t, expected.message, err.Message)
+		}
+	})
+}
+
+func TestWritePage(t *testing.T) {
+	sc := &config.ServerConfig{Protocol: "https", Host: "company.com"}
+
+	t.Run("offset", func(t *testing.T) {
+		ctx, w := newListContext("/api/users?page=2&size=2&status=active")
+		q, _ := ParseListQuery(ctx, WithFilterField("status"))
+		WritePage(ctx, sc, NewPage([]string{"c", "d"}, q, 5))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"items":["c","d"],"page":2,"size":2,"totalItems":5,"totalPages":3}`, w.Body.String())
+		assert.Equal(t,
+			`<https://company.com/api/users?page=3&size=2&status=active>; rel="next", `+
+				`<https://company.com/api/users?page=1&size=2&status=active>; rel="prev"`,
+			w.Header().Get(LinkHeaderKey))
+	})
+
+	t.Run("first and last page", func(t *testing.T) {
+		ctx, w := newListContext("/api/users")
+		q, _ := ParseListQuery(ctx)
+		WritePage(ctx, nil, NewPage[string](nil, q, 0))
+		assert.JSONEq(t, `{"items":[],"page":1,"size":20}`, w.Body.String())
+		assert.Empty(t, w.Header().Get(LinkHeaderKey))
+
+		ctx, w = newListContext("/api/users?page=3&size=2")
+		q, _ = ParseListQuery(ctx)
+		WritePage(ctx, nil, NewPage([]string{"e"}, q, 5))
+		assert.Equal(t, `</api/users?page=2&size=2>; rel="prev"`, w.Header().Get(LinkHeaderKey))
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		ctx, w := newListContext("/api/users?cursor=b&size=2")
+		q, _ := ParseListQuery(ctx, WithCursor())
+		WritePage(ctx, sc, NewCursorPage([]string{"c", "d"}, q, "d", "a"))
+
+		assert.JSONEq(t, `{"items":["c","d"],"size":2,"nextCursor":"d","prevCursor":"a"}`, w.Body.String())
+		assert.Equal(t,
+			`<https://company.com/api/users?cursor=d&size=2>; rel="next", `+
+				`<https://company.com/api/users?cursor=a&size=2>; rel="prev"`,
+			w.Header().Get(LinkHeaderKey))
+	})
+}
+
+func TestCursor(t *testing.T) {
+	type key struct {
+		Name string `json:"name"`
+		Id   int    `json:"id"`
+	}
+
+	cursor, err := EncodeCursor(key{Name: "test", Id: 1})
+	assert.Nil(t, err)
+
+	out := new(key)
+	assert.Nil(t, DecodeCursor(cursor, out))
+	assert.Equal(t, key{Name: "test", Id: 1}, *out)
+
+	err = DecodeCursor("not a cursor", out)
+	assert.NotNil(t, err)
+	assert.Equal(t, CursorParam, err.Field)
+
+	_, err = EncodeCursor(make(chan int))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,309 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RateLimitLimitHeaderKey     = "RateLimit-Limit"
+	RateLimitRemainingHeaderKey = "RateLimit-Remaining"
+	RateLimitResetHeaderKey     = "RateLimit-Reset"
+	RetryAfterHeaderKey         = "Retry-After"
+
+	rateLimitStoreErrMsg      = "Rate limit store failed, the request is allowed: %s"
+	invalidLimitMsg           = "invalid rate limit %+v: requests and period must be positive"
+	invalidRouteLimitMsg      = "invalid rate limit of route '%s': %s"
+	defaultRateLimitIdleAfter = 10 * time.Minute
+)
+
+type (
+	// Limit defines the number of requests that are allowed per period.
+	// Burst is the maximum number of requests that are allowed at once. Default burst is Requests.
+	Limit struct {
+		Requests int
+		Period   time.Duration
+		Burst    int
+	}
+
+	// RateLimitResult is the result of taking a token from a bucket.
+	RateLimitResult struct {
+		Allowed    bool
+		Limit      int
+		Remaining  int
+		Reset      time.Duration
+		RetryAfter time.Duration
+	}
+
+	// RateLimitStore keeps the token buckets of the rate limiter.
+	RateLimitStore interface {
+		Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+	}
+
+	// MemoryRateLimitStore is a RateLimitStore that keeps the token buckets in memory.
+	// Buckets that were not used for the idle timeout are evicted.
+	MemoryRateLimitStore struct {
+		idleAfter time.Duration
+		now       func() time.Time
+
+		mu        sync.Mutex
+		buckets   map[string]*tokenBucket
+		lastSweep time.Time
+	}
+
+	// tokenBucket represents the state of a single rate limit key.
+	tokenBucket struct {
+		tokens float64
+		last   time.Time
+	}
+
+	// RateLimitKeyFunc returns the key that is used to rate limit the request.
+	RateLimitKeyFunc func(ctx *gin.Context) string
+
+	// RateLimitConfig represents the configuration of the RateLimit middleware.
+	RateLimitConfig struct {
+		Limit       Limit
+		KeyFunc     RateLimitKeyFunc
+		Store       RateLimitStore
+		RouteLimits map[string]Limit
+	}
+
+	// RateLimitOption is an option that can be used to configure the RateLimit middleware.
+	RateLimitOption func(config *RateLimitConfig)
+)
+
+// PerSecond returns a Limit of n requests per second.
+func PerSecond(n int) Limit {
+	return Limit{Requests: n, Period: time.Second}
+}
+
+// PerMinute returns a Limit of n requests per minute.
+func PerMinute(n int) Limit {
+	return Limit{Requests: n, Period: time.Minute}
+}
+
+// burst returns the size of the bucket.
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+// validate checks if the limit allows a positive number of requests per positive period.
+func (l Limit) validate() error {
+	if l.Requests <= 0 || l.Period <= 0 {
+		return fmt.Errorf(invalidLimitMsg, l)
+	}
+	return nil
+}
+
+// rate returns the number of tokens that are added to the bucket per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// NewMemoryRateLimitStore returns a MemoryRateLimitStore that evicts buckets that were not used for idleAfter.
+// Default idle timeout is 10 minutes.
+func NewMemoryRateLimitStore(idleAfter time.Duration) *MemoryRateLimitStore {
+	if idleAfter <= 0 {
+		idleAfter = defaultRateLimitIdleAfter
+	}
+	return &MemoryRateLimitStore{
+		idleAfter: idleAfter,
+		now:       time.Now,
+		buckets:   make(map[string]*tokenBucket),
+	}
+}
+
+// Take takes a token from the bucket of the key.
+// The method returns an error if the requests or the period of the limit are not positive.
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit Limit) (RateLimitResult, error) {
+	if err := limit.validate(); err != nil {
+		return RateLimitResult{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evict(now)
+
+	burst := float64(limit.burst())
+	rate := limit.rate()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	result := RateLimitResult{Limit: limit.burst()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((burst - b.tokens) / rate)
+	return result, nil
+}
+
+// Len returns the number of buckets in the store.
+func (s *MemoryRateLimitStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+// evict removes the buckets that were not used for the idle timeout.
+// The buckets are checked at most once per idle timeout.
+func (s *MemoryRateLimitStore) evict(now time.Time) {
+	if now.Sub(s.lastSweep) < s.idleAfter {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= s.idleAfter {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// KeyByIP rate limits requests by the client IP.
+func KeyByIP(ctx *gin.Context) string {
+	return ctx.ClientIP()
+}
+
+// KeyByConsumerId rate limits requests by the Consumer-ID header.
+// Requests without the header are rate limited by the client IP.
+func KeyByConsumerId(ctx *gin.Context) string {
+	if consumerId := strings.TrimSpace(ctx.GetHeader(ConsumerIdHeaderKey)); consumerId != "" {
+		return consumerId
+	}
+	return ctx.ClientIP()
+}
+
+// KeyByUser rate limits requests by the user that was authenticated by an authentication middleware.
+// Requests without an authenticated user are rate limited by the client IP.
+func KeyByUser(ctx *gin.Context) string {
+	if user := ctx.GetString(AuthUserKey); user != "" {
+		return user
+	}
+	return ctx.ClientIP()
+}
+
+// WithRateLimitKey is a RateLimit option that defines how requests are grouped. Default key function is KeyByIP.
+func WithRateLimitKey(keyFunc RateLimitKeyFunc) RateLimitOption {
+	return func(config *RateLimitConfig) {
+		config.KeyFunc = keyFunc
+	}
+}
+
+// WithRateLimitStore is a RateLimit option that defines the store of the token buckets.
+// Default store is a MemoryRateLimitStore.
+func WithRateLimitStore(store RateLimitStore) RateLimitOption {
+	return func(config *RateLimitConfig) {
+		config.Store = store
+	}
+}
+
+// WithRouteLimit is a RateLimit option that defines a separate limit for a route.
+// The route is a route template (gin FullPath), optionally prefixed by the method, for example
+// "/users/:id" or "GET /users/:id". A limit with a method takes precedence.
+func WithRouteLimit(route string, limit Limit) RateLimitOption {
+	return func(config *RateLimitConfig) {
+		config.RouteLimits[route] = limit
+	}
+}
+
+// RateLimit is a gin middleware that limits the number of requests using a token bucket per key.
+// The RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers are set on every response.
+// The method returns a too many requests error with the Retry-After header if the limit is exceeded.
+// If the store fails the request is allowed.
+// The method panics if the requests or the period of the limit or of one of the route limits are not positive.
+func RateLimit(limit Limit, opts ...RateLimitOption) gin.HandlerFunc {
+	config := &RateLimitConfig{
+		Limit:       limit,
+		KeyFunc:     KeyByIP,
+		RouteLimits: make(map[string]Limit),
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if err := config.Limit.validate(); err != nil {
+		panic(err.Error())
+	}
+	for route, routeLimit := range config.RouteLimits {
+		if err := routeLimit.validate(); err != nil {
+			panic(fmt.Sprintf(invalidRouteLimitMsg, route, err.Error()))
+		}
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore(defaultRateLimitIdleAfter)
+	}
+
+	return func(ctx *gin.Context) {
+		key := config.KeyFunc(ctx)
+		limit := config.Limit
+		if route, routeLimit, ok := config.routeLimit(ctx); ok {
+			key = route + " " + key
+			limit = routeLimit
+		}
+
+		result, err := config.Store.Take(ctx.Request.Context(), key, limit)
+		if err != nil {
+			logger.Errorf(rateLimitStoreErrMsg, err.Error())
+			ctx.Next()
+			return
+		}
+
+		ctx.Header(RateLimitLimitHeaderKey, strconv.Itoa(result.Limit))
+		ctx.Header(RateLimitRemainingHeaderKey, strconv.Itoa(result.Remaining))
+		ctx.Header(RateLimitResetHeaderKey, strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			ctx.Header(RetryAfterHeaderKey, strconv.Itoa(retryAfter))
+			AbortWithError(ctx, errors.Newf(
+				errors.ErrCodeRateLimitExceeded,
+				http.StatusTooManyRequests,
+				errors.ErrMsg[errors.ErrCodeRateLimitExceeded],
+				retryAfter,
+			))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// routeLimit returns the limit of the route of the request.
+func (config *RateLimitConfig) routeLimit(ctx *gin.Context) (string, Limit, bool) {
+	route := ctx.Request.Method + " " + ctx.FullPath()
+	if limit, ok := config.RouteLimits[route]; ok {
+		return route, limit, true
+	}
+	route = ctx.FullPath()
+	limit, ok := config.RouteLimits[route]
+	return route, limit, ok
+}
+
+// secondsToDuration converts seconds to a time.Duration.
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// ceilSeconds rounds the duration up to whole seconds.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
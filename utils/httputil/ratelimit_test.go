@@ -0,0 +1,194 @@
+package httputil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	rateLimitResponse = `{"errors":[{"code":"RATE_LIMIT_EXCEEDED","status":429,"message":"Rate limit exceeded, retry after 60 seconds","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+)
+
+type mockClock struct {
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+func getMockRateLimitStore(idleAfter time.Duration) (*MemoryRateLimitStore, *mockClock) {
+	clock := &mockClock{now: time.Now()}
+	store := NewMemoryRateLimitStore(idleAfter)
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	store, clock := getMockRateLimitStore(time.Hour)
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 2}
+
+	result, _ := store.Take(context.Background(), "key", limit)
+	assert.Equal(t, RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, result)
+	result, _ = store.Take(context.Background(), "key", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, _ = store.Take(context.Background(), "key", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	clock.now = clock.now.Add(time.Second)
+	result, _ = store.Take(context.Background(), "key", limit)
+	assert.True(t, result.Allowed)
+
+	result, _ = store.Take(context.Background(), "other", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestMemoryRateLimitStore_InvalidLimit(t *testing.T) {
+	store, _ := getMockRateLimitStore(time.Hour)
+	for _, limit := range []Limit{{Requests: 1}, {Period: time.Second}, {Requests: -1, Period: time.Second}} {
+		_, err := store.Take(context.Background(), "key", limit)
+		assert.ErrorContains(t, err, "requests and period must be positive")
+	}
+}
+
+func TestMemoryRateLimitStore_Evict(t *testing.T) {
+	store, clock := getMockRateLimitStore(time.Minute)
+	limit := PerSecond(1)
+
+	_, _ = store.Take(context.Background(), "a", limit)
+	clock.now = clock.now.Add(30 * time.Second)
+	_, _ = store.Take(context.Background(), "b", limit)
+	assert.Equal(t, 2, store.Len())
+
+	clock.now = clock.now.Add(45 * time.Second)
+	_, _ = store.Take(context.Background(), "c", limit)
+	assert.Equal(t, 2, store.Len())
+}
+
+func TestRateLimit(t *testing.T) {
+	router := setupMockRouter(setMockTraceId, RateLimit(Limit{Requests: 1, Period: time.Minute, Burst: 2}))
+
+	for i, statusCode := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		t.Run(fmt.Sprintf("request %d", i+1), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, statusCode, w.Code)
+			assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeaderKey))
+			if statusCode == http.StatusTooManyRequests {
+				assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeaderKey))
+				assert.Equal(t, "60", w.Header().Get(RetryAfterHeaderKey))
+				assert.Equal(t, rateLimitResponse, w.Body.String())
+			} else {
+				assert.Equal(t, fmt.Sprint(1-i), w.Header().Get(RateLimitRemainingHeaderKey))
+				assert.Empty(t, w.Header().Get(RetryAfterHeaderKey))
+			}
+		})
+	}
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyFunc RateLimitKeyFunc
+		setup   func(req *http.Request)
+	}{
+		{name: "consumer id", keyFunc: KeyByConsumerId, setup: func(req *http.Request) {
+			req.Header.Set(ConsumerIdHeaderKey, "consumer")
+		}},
+		{name: "user", keyFunc: KeyByUser, setup: func(req *http.Request) {
+			req.SetBasicAuth("admin", "admin")
+		}},
+		{name: "custom", keyFunc: func(ctx *gin.Context) string { return ctx.Query("tenant") }, setup: func(req *http.Request) {
+			req.URL.RawQuery = "tenant=acme"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupMockRouter(BasicAuth(getMockAccount()), RateLimit(PerMinute(1), WithRateLimitKey(tt.keyFunc)))
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+			req.SetBasicAuth("user", "user")
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodGet, "/login", nil)
+			req.SetBasicAuth("user", "user")
+			tt.setup(req)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest(http.MethodGet, "/login", nil)
+			req.SetBasicAuth("user", "user")
+			tt.setup(req)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		})
+	}
+}
+
+func TestRateLimit_RouteLimits(t *testing.T) {
+	router := setupMockRouter(RateLimit(PerMinute(1), WithRouteLimit("GET /search", PerMinute(2))))
+	router.GET("/search", func(ctx *gin.Context) { ctx.String(http.StatusOK, "OK") })
+
+	for _, tt := range []struct {
+		path       string
+		statusCode int
+	}{
+		{path: "/search", statusCode: http.StatusOK},
+		{path: "/search", statusCode: http.StatusOK},
+		{path: "/login", statusCode: http.StatusOK},
+		{path: "/search", statusCode: http.StatusTooManyRequests},
+		{path: "/login", statusCode: http.StatusTooManyRequests},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.statusCode, w.Code, tt.path)
+	}
+}
+
+func TestRateLimit_InvalidLimit(t *testing.T) {
+	assert.PanicsWithValue(t, "invalid rate limit {Requests:1 Period:0s Burst:0}: requests and period must be positive",
+		func() { RateLimit(Limit{Requests: 1}) })
+	assert.PanicsWithValue(t, "invalid rate limit {Requests:0 Period:1s Burst:0}: requests and period must be positive",
+		func() { RateLimit(PerSecond(0)) })
+	assert.PanicsWithValue(t,
+		"invalid rate limit of route 'GET /search': invalid rate limit {Requests:0 Period:1m0s Burst:0}: requests and period must be positive",
+		func() { RateLimit(PerMinute(1), WithRouteLimit("GET /search", PerMinute(0))) })
+	assert.NotPanics(t, func() { RateLimit(PerMinute(1), WithRouteLimit("GET /search", PerSecond(1))) })
+}
+
+func TestRateLimit_StoreError(t *testing.T) {
+	store := rateLimitStoreFunc(func(context.Context, string, Limit) (RateLimitResult, error) {
+		return RateLimitResult{}, fmt.Errorf("unavailable")
+	})
+	router := setupMockRouter(RateLimit(PerMinute(1), WithRateLimitStore(store)))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(RateLimitLimitHeaderKey))
+}
+
+type rateLimitStoreFunc func(ctx context.Context, key string, limit Limit) (RateLimitResult, error)
+
+func (f rateLimitStoreFunc) Take(ctx context.Context, key string, limit Limit) (RateLimitResult, error) {
+	return f(ctx, key, limit)
+}
@@ -1,9 +1,13 @@
 package httputil
 
 import (
+	"net/http"
 	"net/url"
 
 	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/gin-gonic/gin"
 	"github.com/jarcoal/httpmock"
 )
 
@@ -17,11 +21,14 @@ const (
 	AcceptHeaderKey           = "Accept"
 	AuthorizationHeaderKey    = "Authorization"
 	ApplicationJsonMIMEType   = "application/json"
+	ApplicationYamlMIMEType   = "application/yaml"
+	ApplicationXmlMIMEType    = "application/xml"
 	TextPlainMIMEType         = "text/plain"
 	XWWWFromUrlEncodeMIMEType = "application/x-www-form-urlencoded"
 	SwaggerPath               = "/swagger/*any"
 	SwaggerSpecPathFormat     = "%s/swagger/doc.json"
 	SwaggerUriPathFormat      = "%s/swagger/index.html"
+	ErrorContextKey           = "error"
 )
 
 // RestMsg represents a message returned by a REST API
@@ -50,6 +57,52 @@ func GetServerURL(sc *config.ServerConfig, apiBasePath string) *url.URL {
 	return serverURL
 }
 
+// AbortWithError sets the trace id of the request on the error, writes the error to the gin context
+// and aborts the request. The error is stored in the gin context and can be retrieved using GetError.
+// The status 500 Internal Server Error is used if the error does not have a status.
+func AbortWithError(ctx *gin.Context, err *errors.Error) {
+	abortWithFormat(ctx, ApplicationJsonMIMEType, err)
+}
+
+// AbortWithErrors sets the trace id of the request on the errors, writes the errors to the gin context
+// and aborts the request with the status of the first error, which is stored in the gin context.
+// It can be used to return the errors of multiple fields at once.
+func AbortWithErrors(ctx *gin.Context, errs ...*errors.Error) {
+	abortWithFormat(ctx, ApplicationJsonMIMEType, errs...)
+}
+
+// abortWithFormat sets the trace id of the request on the errors, writes the errors in the format, see render,
+// and aborts the request with the status of the first error, which is stored in the gin context.
+// The status 500 Internal Server Error is used for errors without a status.
+func abortWithFormat(ctx *gin.Context, format string, errs ...*errors.Error) {
+	if len(errs) == 0 {
+		return
+	}
+	traceId := GetTraceId(ctx)
+	list := make([]errors.Error, 0, len(errs))
+	for _, err := range errs {
+		if err.Status == 0 {
+			err.Status = http.StatusInternalServerError
+		}
+		err.TraceId = traceId
+		list = append(list, *err)
+		logger.Info(err.Message)
+	}
+	ctx.Set(ErrorContextKey, errs[0])
+	ctx.Abort()
+	render(ctx, format, errs[0].Status, errors.Errors{Errors: list})
+}
+
+// GetError returns the error that was written by AbortWithError or the first error that was written by AbortWithErrors.
+func GetError(ctx *gin.Context) (*errors.Error, bool) {
+	v, ok := ctx.Get(ErrorContextKey)
+	if !ok {
+		return nil, false
+	}
+	err, ok := v.(*errors.Error)
+	return err, ok
+}
+
 // NewStringToJsonResponder is a custom httpmock.Responder that takes the status code and a json string body
 // and creates a responder for a http mock. This is a useful function when unit testing rest API responses.
 func NewStringToJsonResponder(statusCode int, body string) httpmock.Responder {
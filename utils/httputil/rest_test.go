@@ -2,9 +2,12 @@ package httputil
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,3 +32,53 @@ func TestNewStringToJsonResponder(t *testing.T) {
 	responder := NewStringToJsonResponder(http.StatusOK, "")
 	assert.NotNil(t, responder)
 }
+
+func TestGetError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	_, ok := GetError(ctx)
+	assert.False(t, ok)
+
+	AbortWithError(ctx, errors.ForbiddenError("not allowed"))
+	err, ok := GetError(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, errors.ErrCodeInsufficientAccess, err.Code)
+	assert.Equal(t, http.StatusForbidden, ctx.Writer.Status())
+}
+
+func TestAbortWithError_WithoutStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+
+	AbortWithError(ctx, errors.New(errors.ErrCodeInvalidStructTag, 0, "invalid tag"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":500`)
+}
+
+func TestAbortWithErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set(TraceIDHeaderKey, "trace")
+
+	AbortWithErrors(ctx)
+	assert.False(t, ctx.IsAborted())
+
+	first := errors.BadRequestError("name is required")
+	first.Field = "name"
+	AbortWithErrors(ctx, first, errors.BadRequestError("age is not valid"))
+
+	assert.True(t, ctx.IsAborted())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"errors":[`+
+		`{"code":"BAD_REQUEST","status":400,"message":"name is required","field":"name","traceId":"trace"},`+
+		`{"code":"BAD_REQUEST","status":400,"message":"age is not valid","traceId":"trace"}]}`, w.Body.String())
+	err, ok := GetError(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, first, err)
+}
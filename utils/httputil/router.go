@@ -1,23 +1,229 @@
 package httputil
 
 import (
+	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/atselvan/go-utils/utils/config"
 	"github.com/atselvan/go-utils/utils/errors"
 	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/metrics"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	MetricsPath = "/metrics"
+
+	trustedProxiesErrMsg = "Unable to set the trusted proxies %v: %s"
+)
+
+type (
+	// RouterConfig represents the configuration of the router that is created by NewRouter.
+	RouterConfig struct {
+		Mode                   string
+		TrustedProxies         []string
+		PreMiddlewares         []gin.HandlerFunc
+		Logger                 gin.HandlerFunc
+		Recovery               gin.HandlerFunc
+		Middlewares            []gin.HandlerFunc
+		HealthEndpoint         bool
+		HealthRegistry         *HealthRegistry
+		Metrics                *metrics.Metrics
+		NoRoute                gin.HandlerFunc
+		NoMethod               gin.HandlerFunc
+		HandleMethodNotAllowed bool
+		BasePath               string
+		Routes                 func(group *gin.RouterGroup)
+	}
+
+	// RouterOption is an option that can be used to configure the router that is created by NewRouter.
+	RouterOption func(config *RouterConfig)
+)
+
+// WithMode is a NewRouter option that sets the gin mode. Default mode is gin.ReleaseMode.
+func WithMode(mode string) RouterOption {
+	return func(config *RouterConfig) {
+		config.Mode = mode
+	}
+}
+
+// WithTrustedProxies is a NewRouter option that defines the network origins (IPv4 addresses, IPv4 CIDRs,
+// IPv6 addresses or IPv6 CIDRs) that are trusted to set the client IP headers. By default, all proxies are trusted.
+// NewRouter panics if one of the proxies is not valid.
+func WithTrustedProxies(proxies ...string) RouterOption {
+	return func(config *RouterConfig) {
+		config.TrustedProxies = proxies
+	}
+}
+
+// WithPreMiddleware is a NewRouter option that registers middlewares before the logger and recovery middlewares.
+func WithPreMiddleware(middleware ...gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.PreMiddlewares = append(config.PreMiddlewares, middleware...)
+	}
+}
+
+// WithMiddleware is a NewRouter option that registers additional middlewares after the default middlewares.
+func WithMiddleware(middleware ...gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.Middlewares = append(config.Middlewares, middleware...)
+	}
+}
+
+// WithLogger is a NewRouter option that replaces the logger.GinZap middleware. A nil logger disables logging.
+func WithLogger(logger gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.Logger = logger
+	}
+}
+
+// WithRecovery is a NewRouter option that replaces the gin.Recovery middleware. A nil recovery disables recovery.
+func WithRecovery(recovery gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.Recovery = recovery
+	}
+}
+
+// WithHealthEndpoint is a NewRouter option that registers the Health controller on the ApiHealthPath.
+func WithHealthEndpoint() RouterOption {
+	return func(config *RouterConfig) {
+		config.HealthEndpoint = true
+	}
+}
+
+// WithHealthRegistry is a NewRouter option that registers the health, liveness and readiness controllers
+// of the HealthRegistry, see HealthRegistry.RegisterRoutes. The option takes precedence over WithHealthEndpoint.
+func WithHealthRegistry(h *HealthRegistry) RouterOption {
+	return func(config *RouterConfig) {
+		config.HealthRegistry = h
+	}
+}
+
+// WithMetrics is a NewRouter option that registers the metrics middleware before the logger and recovery
+// middlewares, so that recovered panics are recorded, and the metrics controller on the MetricsPath.
+func WithMetrics(m *metrics.Metrics) RouterOption {
+	return func(config *RouterConfig) {
+		config.Metrics = m
+	}
+}
+
+// WithNoRoute is a NewRouter option that replaces the NoRoute controller.
+func WithNoRoute(handler gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.NoRoute = handler
+	}
+}
+
+// WithNoMethod is a NewRouter option that replaces the MethodNotAllowed controller.
+func WithNoMethod(handler gin.HandlerFunc) RouterOption {
+	return func(config *RouterConfig) {
+		config.NoMethod = handler
+	}
+}
+
+// WithoutMethodNotAllowed is a NewRouter option that disables the method not allowed handling,
+// requests on known endpoints with a method that is not configured are handled by the NoRoute controller.
+func WithoutMethodNotAllowed() RouterOption {
+	return func(config *RouterConfig) {
+		config.HandleMethodNotAllowed = false
+	}
+}
+
+// WithBasePath is a NewRouter option that calls routes with a router group of the base path, for example "/api/v1",
+// to register the routes of the service under the base path. The routes that are registered on the router itself,
+// like the metrics and health routes, are not prefixed with the base path.
+func WithBasePath(basePath string, routes func(group *gin.RouterGroup)) RouterOption {
+	return func(config *RouterConfig) {
+		config.BasePath = "/" + strings.Trim(basePath, "/")
+		config.Routes = routes
+	}
+}
+
+// WithCORS is a NewRouter option that registers the CORS middleware.
+func WithCORS(cors CORSConfig) RouterOption {
+	return WithMiddleware(CORS(cors))
+}
+
+// WithSecurityHeaders is a NewRouter option that registers the SecurityHeaders middleware.
+func WithSecurityHeaders(headers SecurityHeadersConfig) RouterOption {
+	return WithMiddleware(SecurityHeaders(headers))
+}
+
+// WithServerConfig is a NewRouter option that registers the middlewares as defined in the ServerConfig:
+//   - SecurityHeaders.
+//   - CORS, if allowed origins are configured.
+//   - BodyLimit, if a max request body size is configured.
+//   - Timeout, if a request timeout is configured.
+func WithServerConfig(sc *config.ServerConfig) RouterOption {
+	return func(config *RouterConfig) {
+		WithSecurityHeaders(NewSecurityHeadersConfig(sc))(config)
+		if len(sc.CORSAllowedOrigins) > 0 {
+			WithCORS(NewCORSConfig(sc))(config)
+		}
+		if sc.MaxRequestBodySize > 0 {
+			WithMiddleware(BodyLimit(sc.MaxRequestBodySize))(config)
+		}
+		if sc.RequestTimeout > 0 {
+			WithMiddleware(Timeout(sc.RequestTimeout))(config)
+		}
+	}
+}
+
 // NewRouter returns a new gin router which is configured with some settings for logging,
 // auto recovery in case of panics and default handlers for NoRoute and MethodNotAllowed.
-func NewRouter() *gin.Engine {
-	gin.SetMode(gin.ReleaseMode)
+// The middlewares are registered in the following order:
+//   - the metrics middleware of WithMetrics.
+//   - the middlewares of WithPreMiddleware.
+//   - logger.GinZap or the logger of WithLogger.
+//   - gin.Recovery or the recovery of WithRecovery.
+//   - the middlewares of WithMiddleware and the other middleware options.
+func NewRouter(opts ...RouterOption) *gin.Engine {
+	config := &RouterConfig{
+		Mode:                   gin.ReleaseMode,
+		Logger:                 logger.GinZap(),
+		Recovery:               gin.Recovery(),
+		NoRoute:                NoRoute,
+		NoMethod:               MethodNotAllowed,
+		HandleMethodNotAllowed: true,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	gin.SetMode(config.Mode)
 	r := gin.New()
-	r.Use(logger.GinZap())
-	r.Use(gin.Recovery())
-	r.NoRoute(NoRoute)
-	r.HandleMethodNotAllowed = true
-	r.NoMethod(MethodNotAllowed)
+	if config.TrustedProxies != nil {
+		if err := r.SetTrustedProxies(config.TrustedProxies); err != nil {
+			// gin trusts all proxies if the trusted proxies are not valid, which would allow spoofing the client IP
+			panic(fmt.Sprintf(trustedProxiesErrMsg, config.TrustedProxies, err.Error()))
+		}
+	}
+	if config.Metrics != nil {
+		r.Use(config.Metrics.Middleware())
+	}
+	r.Use(config.PreMiddlewares...)
+	if config.Logger != nil {
+		r.Use(config.Logger)
+	}
+	if config.Recovery != nil {
+		r.Use(config.Recovery)
+	}
+	r.Use(config.Middlewares...)
+	r.NoRoute(config.NoRoute)
+	r.HandleMethodNotAllowed = config.HandleMethodNotAllowed
+	r.NoMethod(config.NoMethod)
+	if config.Metrics != nil {
+		r.GET(MetricsPath, config.Metrics.Handler())
+	}
+	if config.HealthRegistry != nil {
+		config.HealthRegistry.RegisterRoutes(r)
+	} else if config.HealthEndpoint {
+		r.GET(ApiHealthPath, Health)
+	}
+	if config.Routes != nil {
+		config.Routes(r.Group(config.BasePath))
+	}
 	return r
 }
 
@@ -2,11 +2,16 @@ package httputil
 
 import (
 	"bytes"
-	"github.com/stretchr/testify/assert"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
 )
 
 const (
@@ -59,3 +64,120 @@ func TestMethodNotAllowed(t *testing.T) {
 	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 	assert.Equal(t, methodNotAllowedResponse, readResponseBody(w.Body, t))
 }
+
+func TestNewRouter_WithServerConfig(t *testing.T) {
+	r := NewRouter(WithServerConfig(&config.ServerConfig{
+		Protocol:           "https",
+		CORSAllowedOrigins: []string{"https://example.com"},
+	}))
+	r.GET(healthApiPath, Health)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, healthApiPath, nil)
+	req.Header.Set(OriginHeaderKey, "https://example.com")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://example.com", w.Header().Get(AccessControlAllowOriginHeaderKey))
+	assert.NotEmpty(t, w.Header().Get(StrictTransportSecurityHeaderKey))
+	assert.Equal(t, "nosniff", w.Header().Get(ContentTypeOptionsHeaderKey))
+}
+
+func TestNewRouter_Options(t *testing.T) {
+	var order []string
+	track := func(name string) gin.HandlerFunc {
+		return func(ctx *gin.Context) {
+			order = append(order, name)
+			ctx.Next()
+		}
+	}
+	r := NewRouter(
+		WithMode(gin.TestMode),
+		WithTrustedProxies("10.0.0.0/8"),
+		WithPreMiddleware(track("pre")),
+		WithLogger(track("logger")),
+		WithRecovery(nil),
+		WithMiddleware(track("post")),
+		WithBasePath("/api/v1/", func(group *gin.RouterGroup) {
+			assert.Equal(t, "/api/v1", group.BasePath())
+			group.GET("/ip", func(ctx *gin.Context) { ctx.String(http.StatusOK, ctx.ClientIP()) })
+		}),
+		WithHealthEndpoint(),
+	)
+	defer gin.SetMode(gin.ReleaseMode)
+	assert.Equal(t, gin.TestMode, gin.Mode())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, ApiHealthPath, nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, successResponse, readResponseBody(w.Body, t))
+	assert.Equal(t, []string{"pre", "logger", "post"}, order)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/ip", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "192.168.0.1")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "192.168.0.1", w.Body.String())
+
+	// the routes of the router itself are not prefixed with the base path
+	assert.Equal(t, "/", r.BasePath())
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1"+ApiHealthPath, nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestNewRouter_InvalidTrustedProxies(t *testing.T) {
+	assert.PanicsWithValue(t, `Unable to set the trusted proxies [10.0.0.0/8 proxy]: invalid IP address: proxy`, func() {
+		NewRouter(WithTrustedProxies("10.0.0.0/8", "proxy"))
+	})
+}
+
+func TestNewRouter_Handlers(t *testing.T) {
+	t.Run("custom handlers", func(t *testing.T) {
+		r := NewRouter(
+			WithNoRoute(func(ctx *gin.Context) { ctx.String(http.StatusNotFound, "no route") }),
+			WithNoMethod(func(ctx *gin.Context) { ctx.String(http.StatusMethodNotAllowed, "no method") }),
+		)
+		r.GET(healthApiPath, Health)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, notFoundApiPath, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "no route", w.Body.String())
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodPost, healthApiPath, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, "no method", w.Body.String())
+	})
+
+	t.Run("without method not allowed", func(t *testing.T) {
+		r := NewRouter(WithoutMethodNotAllowed())
+		r.GET(healthApiPath, Health)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, healthApiPath, nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, pathNotfoundResponse, readResponseBody(w.Body, t))
+	})
+}
+
+func TestNewRouter_WithMetrics(t *testing.T) {
+	r := NewRouter(WithMetrics(metrics.New(metrics.WithRegistry(prometheus.NewRegistry()))), WithHealthEndpoint(),
+		WithBasePath("/api", func(group *gin.RouterGroup) {}))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, healthApiPath, nil)
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, MetricsPath, nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="/health",status_class="2xx"} 1`)
+}
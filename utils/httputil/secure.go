@@ -0,0 +1,76 @@
+package httputil
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StrictTransportSecurityHeaderKey = "Strict-Transport-Security"
+	ContentTypeOptionsHeaderKey      = "X-Content-Type-Options"
+	FrameOptionsHeaderKey            = "X-Frame-Options"
+	ContentSecurityPolicyHeaderKey   = "Content-Security-Policy"
+	ReferrerPolicyHeaderKey          = "Referrer-Policy"
+
+	DefaultHSTSMaxAge     = 365 * 24 * time.Hour
+	DefaultFrameOptions   = "DENY"
+	DefaultReferrerPolicy = "strict-origin-when-cross-origin"
+)
+
+// SecurityHeadersConfig represents the configuration of the SecurityHeaders middleware.
+// The Strict-Transport-Security header is only set if HSTS is true.
+// The Content-Security-Policy header is only set if a policy is configured.
+type SecurityHeadersConfig struct {
+	HSTS                  bool
+	HSTSMaxAge            time.Duration
+	FrameOptions          string
+	ContentSecurityPolicy string
+	ReferrerPolicy        string
+}
+
+// NewSecurityHeadersConfig returns the SecurityHeadersConfig that is defined in the ServerConfig.
+// HSTS is enabled if the ServerConfig.Protocol is https.
+func NewSecurityHeadersConfig(sc *config.ServerConfig) SecurityHeadersConfig {
+	return SecurityHeadersConfig{
+		HSTS:                  sc.Protocol == "https",
+		HSTSMaxAge:            sc.HSTSMaxAge,
+		FrameOptions:          sc.FrameOptions,
+		ContentSecurityPolicy: sc.ContentSecurityPolicy,
+		ReferrerPolicy:        sc.ReferrerPolicy,
+	}
+}
+
+// SecurityHeaders is a gin middleware that sets security related response headers.
+// default values:
+//
+//	SecurityHeadersConfig.HSTSMaxAge = 1 year
+//	SecurityHeadersConfig.FrameOptions = DENY
+//	SecurityHeadersConfig.ReferrerPolicy = strict-origin-when-cross-origin
+func SecurityHeaders(config SecurityHeadersConfig) gin.HandlerFunc {
+	if config.HSTSMaxAge <= 0 {
+		config.HSTSMaxAge = DefaultHSTSMaxAge
+	}
+	if config.FrameOptions == "" {
+		config.FrameOptions = DefaultFrameOptions
+	}
+	if config.ReferrerPolicy == "" {
+		config.ReferrerPolicy = DefaultReferrerPolicy
+	}
+	hsts := fmt.Sprintf("max-age=%d; includeSubDomains", int(config.HSTSMaxAge.Seconds()))
+
+	return func(ctx *gin.Context) {
+		if config.HSTS {
+			ctx.Header(StrictTransportSecurityHeaderKey, hsts)
+		}
+		ctx.Header(ContentTypeOptionsHeaderKey, "nosniff")
+		ctx.Header(FrameOptionsHeaderKey, config.FrameOptions)
+		ctx.Header(ReferrerPolicyHeaderKey, config.ReferrerPolicy)
+		if config.ContentSecurityPolicy != "" {
+			ctx.Header(ContentSecurityPolicyHeaderKey, config.ContentSecurityPolicy)
+		}
+		ctx.Next()
+	}
+}
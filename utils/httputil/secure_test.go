@@ -0,0 +1,50 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		router := setupMockRouter(SecurityHeaders(SecurityHeadersConfig{}))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(StrictTransportSecurityHeaderKey))
+		assert.Equal(t, "nosniff", w.Header().Get(ContentTypeOptionsHeaderKey))
+		assert.Equal(t, DefaultFrameOptions, w.Header().Get(FrameOptionsHeaderKey))
+		assert.Equal(t, DefaultReferrerPolicy, w.Header().Get(ReferrerPolicyHeaderKey))
+		assert.Empty(t, w.Header().Get(ContentSecurityPolicyHeaderKey))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		router := setupMockRouter(SecurityHeaders(SecurityHeadersConfig{
+			HSTS:                  true,
+			HSTSMaxAge:            24 * time.Hour,
+			FrameOptions:          "SAMEORIGIN",
+			ContentSecurityPolicy: "default-src 'self'",
+			ReferrerPolicy:        "no-referrer",
+		}))
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/login", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "max-age=86400; includeSubDomains", w.Header().Get(StrictTransportSecurityHeaderKey))
+		assert.Equal(t, "SAMEORIGIN", w.Header().Get(FrameOptionsHeaderKey))
+		assert.Equal(t, "no-referrer", w.Header().Get(ReferrerPolicyHeaderKey))
+		assert.Equal(t, "default-src 'self'", w.Header().Get(ContentSecurityPolicyHeaderKey))
+	})
+}
+
+func TestNewSecurityHeadersConfig(t *testing.T) {
+	assert.True(t, NewSecurityHeadersConfig(&config.ServerConfig{Protocol: "https"}).HSTS)
+	assert.False(t, NewSecurityHeadersConfig(&config.ServerConfig{Protocol: "http"}).HSTS)
+}
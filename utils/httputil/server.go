@@ -0,0 +1,22 @@
+package httputil
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/atselvan/go-utils/utils/config"
+)
+
+// NewServer returns a http.Server that listens on the host and port of the ServerConfig and
+// uses the read, read header, write and idle timeouts of the ServerConfig.
+// A timeout of zero means there is no timeout.
+func NewServer(sc *config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              net.JoinHostPort(sc.Host, sc.Port),
+		Handler:           handler,
+		ReadTimeout:       sc.ReadTimeout,
+		ReadHeaderTimeout: sc.ReadHeaderTimeout,
+		WriteTimeout:      sc.WriteTimeout,
+		IdleTimeout:       sc.IdleTimeout,
+	}
+}
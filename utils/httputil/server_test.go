@@ -0,0 +1,30 @@
+package httputil
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewServer(t *testing.T) {
+	sc := &config.ServerConfig{
+		Host:              "localhost",
+		Port:              "8000",
+		ReadTimeout:       time.Second,
+		ReadHeaderTimeout: 2 * time.Second,
+		WriteTimeout:      3 * time.Second,
+		IdleTimeout:       4 * time.Second,
+	}
+	handler := http.NewServeMux()
+	srv := NewServer(sc, handler)
+
+	assert.Equal(t, "localhost:8000", srv.Addr)
+	assert.Equal(t, handler, srv.Handler)
+	assert.Equal(t, time.Second, srv.ReadTimeout)
+	assert.Equal(t, 2*time.Second, srv.ReadHeaderTimeout)
+	assert.Equal(t, 3*time.Second, srv.WriteTimeout)
+	assert.Equal(t, 4*time.Second, srv.IdleTimeout)
+}
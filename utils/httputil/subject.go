@@ -0,0 +1,169 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/atselvan/go-utils/utils/slice"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	SubjectContextKey = "subject"
+
+	subjectVerificationFailedMsg = "Subject token verification failed: %s"
+)
+
+type (
+	// Subject represents the subject that was resolved from a subject token.
+	Subject struct {
+		Id         string
+		ConsumerId string
+		TokenType  string
+		Roles      []string
+		Scopes     []string
+		Claims     map[string]any
+	}
+
+	// SubjectTokenVerifier verifies a subject token and resolves the subject it represents.
+	// Verify should return an error with the code errors.ErrCodeSubjectNotAllowed if the token is valid
+	// but the subject is not allowed to access the service. Any other error is treated as
+	// errors.ErrCodeSubjectUnauthenticated.
+	SubjectTokenVerifier interface {
+		Verify(ctx context.Context, tokenType, token string) (*Subject, *errors.Error)
+	}
+
+	// SubjectTokenVerifierFunc is an adapter to allow the use of ordinary functions as SubjectTokenVerifier.
+	SubjectTokenVerifierFunc func(ctx context.Context, tokenType, token string) (*Subject, *errors.Error)
+
+	// SubjectTokenConfig represents the configuration of the SubjectTokenAuth middleware.
+	SubjectTokenConfig struct {
+		ConsumerIdRequired bool
+	}
+
+	// SubjectTokenOption is an option that can be used to configure the SubjectTokenAuth middleware.
+	SubjectTokenOption func(config *SubjectTokenConfig)
+)
+
+// Verify calls f(ctx, tokenType, token).
+func (f SubjectTokenVerifierFunc) Verify(ctx context.Context, tokenType, token string) (*Subject, *errors.Error) {
+	return f(ctx, tokenType, token)
+}
+
+// WithConsumerIdRequired is a SubjectTokenAuth option that rejects requests without the Consumer-ID header
+// with a errors.ErrCodeMissingMandatoryParameter error.
+func WithConsumerIdRequired() SubjectTokenOption {
+	return func(config *SubjectTokenConfig) {
+		config.ConsumerIdRequired = true
+	}
+}
+
+// SubjectTokenAuth is a gin middleware that authenticates requests based on the Subject-Token
+// and Subject-Token-Type headers.
+// The token type must be one of the allowedTokenTypes and the token is verified using the verifier.
+// The resolved Subject is stored in the gin context and can be retrieved using GetSubject.
+// The method returns an error if:
+//   - the Subject-Token-Type header is not set (errors.ErrCodeSubjectTokenTypeMissing).
+//   - the Subject-Token header is not set (errors.ErrCodeSubjectTokenMissing).
+//   - the token type is not allowed (errors.ErrCodeSubjectTokenTypeInvalid).
+//   - the token is not valid (errors.ErrCodeSubjectUnauthenticated).
+//   - the subject is not allowed (errors.ErrCodeSubjectNotAllowed).
+func SubjectTokenAuth(verifier SubjectTokenVerifier, allowedTokenTypes []string, opts ...SubjectTokenOption) gin.HandlerFunc {
+	config := new(SubjectTokenConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(ctx *gin.Context) {
+		tokenType := strings.TrimSpace(ctx.GetHeader(SubjectTokenTypeHeaderKey))
+		if tokenType == "" {
+			AbortWithError(ctx, errors.New(
+				errors.ErrCodeSubjectTokenTypeMissing,
+				http.StatusUnauthorized,
+				errors.ErrMsg[errors.ErrCodeSubjectTokenTypeMissing],
+			))
+			return
+		}
+
+		token := strings.TrimSpace(ctx.GetHeader(SubjectTokenHeaderKey))
+		if token == "" {
+			AbortWithError(ctx, errors.New(
+				errors.ErrCodeSubjectTokenMissing,
+				http.StatusUnauthorized,
+				errors.ErrMsg[errors.ErrCodeSubjectTokenMissing],
+			))
+			return
+		}
+
+		if !slice.EntryExists(allowedTokenTypes, tokenType) {
+			AbortWithError(ctx, errors.Newf(
+				errors.ErrCodeSubjectTokenTypeInvalid,
+				http.StatusBadRequest,
+				errors.ErrMsg[errors.ErrCodeSubjectTokenTypeInvalid],
+				tokenType,
+				allowedTokenTypes,
+			))
+			return
+		}
+
+		consumerId := strings.TrimSpace(ctx.GetHeader(ConsumerIdHeaderKey))
+		if consumerId == "" && config.ConsumerIdRequired {
+			AbortWithError(ctx, errors.Newf(
+				errors.ErrCodeMissingMandatoryParameter,
+				http.StatusBadRequest,
+				errors.ErrMsg[errors.ErrCodeMissingMandatoryParameter],
+				[]string{ConsumerIdHeaderKey},
+			))
+			return
+		}
+
+		subject, err := verifier.Verify(ctx.Request.Context(), tokenType, token)
+		if err != nil || subject == nil {
+			subjectVerificationFailed(ctx, err)
+			return
+		}
+		subject.TokenType = tokenType
+		if subject.ConsumerId == "" {
+			subject.ConsumerId = consumerId
+		}
+
+		ctx.Set(SubjectContextKey, subject)
+		ctx.Set(AuthUserKey, subject.Id)
+		SetPrincipal(ctx, &Principal{Id: subject.Id, Roles: subject.Roles, Scopes: subject.Scopes})
+		logger.Info(authenticationSuccessMsg)
+		ctx.Next()
+	}
+}
+
+// subjectVerificationFailed writes a subject not allowed error if the verifier returned a
+// errors.ErrCodeSubjectNotAllowed error and a subject unauthenticated error otherwise.
+func subjectVerificationFailed(ctx *gin.Context, err *errors.Error) {
+	if err != nil {
+		logger.Infof(subjectVerificationFailedMsg, err.Message)
+	}
+	if err != nil && err.Code == errors.ErrCodeSubjectNotAllowed {
+		AbortWithError(ctx, errors.New(
+			errors.ErrCodeSubjectNotAllowed,
+			http.StatusForbidden,
+			errors.ErrMsg[errors.ErrCodeSubjectNotAllowed],
+		))
+		return
+	}
+	AbortWithError(ctx, errors.New(
+		errors.ErrCodeSubjectUnauthenticated,
+		http.StatusUnauthorized,
+		errors.ErrMsg[errors.ErrCodeSubjectUnauthenticated],
+	))
+}
+
+// GetSubject returns the Subject that was stored in the gin context by the SubjectTokenAuth middleware.
+func GetSubject(ctx *gin.Context) (*Subject, bool) {
+	v, ok := ctx.Get(SubjectContextKey)
+	if !ok {
+		return nil, false
+	}
+	subject, ok := v.(*Subject)
+	return subject, ok
+}
@@ -0,0 +1,144 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSubjectTokenType = "urn:ietf:params:oauth:token-type:jwt"
+)
+
+var (
+	subjectTokenTypeMissingResponse = `{"errors":[{"code":"SUBJECT_TOKEN_TYPE_MISSING","status":401,"message":"Subject-Token-Type header must be set","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	subjectTokenMissingResponse     = `{"errors":[{"code":"SUBJECT_TOKEN_MISSING","status":401,"message":"Subject-Token header must be set","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	subjectTokenTypeInvalidResponse = `{"errors":[{"code":"SUBJECT_TOKEN_TYPE_INVALID","status":400,"message":"Value of header Subject-Token-Type (invalid) must be one of [urn:ietf:params:oauth:token-type:jwt]","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	subjectUnauthenticatedResponse  = `{"errors":[{"code":"SUBJECT_UNAUTHENTICATED","status":401,"message":"Subject token is not valid","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	subjectNotAllowedResponse       = `{"errors":[{"code":"SUBJECT_NOT_ALLOWED","status":403,"message":"Insufficient access","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+	consumerIdMissingResponse       = `{"errors":[{"code":"MISSING_MANDATORY_PARAMETER","status":400,"message":"Missing mandatory parameters : [Consumer-ID]","traceId":"967ed3d6-33ce-4091-943d-b3a6f8b591be"}]}`
+)
+
+var mockSubjectVerifier = SubjectTokenVerifierFunc(func(_ context.Context, _, token string) (*Subject, *errors.Error) {
+	switch token {
+	case "valid":
+		return &Subject{Id: "user", Roles: []string{"reader"}}, nil
+	case "blocked":
+		return nil, errors.ForbiddenError("blocked")
+	case "not-allowed":
+		return nil, errors.New(errors.ErrCodeSubjectNotAllowed, http.StatusForbidden, "subject is blocked")
+	default:
+		return nil, errors.UnauthorizedError("token expired")
+	}
+})
+
+func setupSubjectRouter(opts ...SubjectTokenOption) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(setMockTraceId, SubjectTokenAuth(mockSubjectVerifier, []string{testSubjectTokenType}, opts...))
+	r.GET("/subject", func(ctx *gin.Context) {
+		subject, ok := GetSubject(ctx)
+		if !ok {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.JSON(http.StatusOK, subject)
+	})
+	return r
+}
+
+func TestSubjectTokenAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []SubjectTokenOption
+		headers    map[string]string
+		statusCode int
+		response   string
+	}{
+		{
+			name:       "token type missing",
+			headers:    map[string]string{SubjectTokenHeaderKey: "valid"},
+			statusCode: http.StatusUnauthorized,
+			response:   subjectTokenTypeMissingResponse,
+		},
+		{
+			name:       "token missing",
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: testSubjectTokenType},
+			statusCode: http.StatusUnauthorized,
+			response:   subjectTokenMissingResponse,
+		},
+		{
+			name:       "token type invalid",
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: "invalid", SubjectTokenHeaderKey: "valid"},
+			statusCode: http.StatusBadRequest,
+			response:   subjectTokenTypeInvalidResponse,
+		},
+		{
+			name:       "unauthenticated",
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: testSubjectTokenType, SubjectTokenHeaderKey: "expired"},
+			statusCode: http.StatusUnauthorized,
+			response:   subjectUnauthenticatedResponse,
+		},
+		{
+			name:       "forbidden error is unauthenticated",
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: testSubjectTokenType, SubjectTokenHeaderKey: "blocked"},
+			statusCode: http.StatusUnauthorized,
+			response:   subjectUnauthenticatedResponse,
+		},
+		{
+			name:       "not allowed",
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: testSubjectTokenType, SubjectTokenHeaderKey: "not-allowed"},
+			statusCode: http.StatusForbidden,
+			response:   subjectNotAllowedResponse,
+		},
+		{
+			name:       "consumer id missing",
+			opts:       []SubjectTokenOption{WithConsumerIdRequired()},
+			headers:    map[string]string{SubjectTokenTypeHeaderKey: testSubjectTokenType, SubjectTokenHeaderKey: "valid"},
+			statusCode: http.StatusBadRequest,
+			response:   consumerIdMissingResponse,
+		},
+		{
+			name: "success",
+			opts: []SubjectTokenOption{WithConsumerIdRequired()},
+			headers: map[string]string{
+				SubjectTokenTypeHeaderKey: testSubjectTokenType,
+				SubjectTokenHeaderKey:     "valid",
+				ConsumerIdHeaderKey:       "consumer",
+			},
+			statusCode: http.StatusOK,
+			response:   `{"Id":"user","ConsumerId":"consumer","TokenType":"urn:ietf:params:oauth:token-type:jwt","Roles":["reader"],"Scopes":null,"Claims":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/subject", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			setupSubjectRouter(tt.opts...).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+			assert.Equal(t, tt.response, w.Body.String())
+		})
+	}
+}
+
+func TestGetSubject(t *testing.T) {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	subject, ok := GetSubject(ctx)
+	assert.False(t, ok)
+	assert.Nil(t, subject)
+
+	ctx.Set(SubjectContextKey, &Subject{Id: "user"})
+	subject, ok = GetSubject(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "user", subject.Id)
+}
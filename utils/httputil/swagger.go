@@ -0,0 +1,306 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/fileutil"
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultSwaggerUIURL is the location the Swagger UI assets are loaded from.
+	// The version is pinned, use WithSwaggerUIIntegrity to verify the assets with subresource integrity hashes or
+	// WithSwaggerUIFS to serve the assets from the server itself, which works with a Content-Security-Policy
+	// that only allows 'self'.
+	DefaultSwaggerUIURL = "https://unpkg.com/swagger-ui-dist@5.17.14"
+
+	swaggerSpecFile        = "/doc.json"
+	swaggerIndexFile       = "/index.html"
+	swaggerInitializerFile = "/swagger-initializer.js"
+	swaggerDisabledMsg     = "The API server swagger docs are disabled"
+	swaggerSpecMissing     = "swagger spec"
+	swaggerSpecInvalid     = "swagger spec is not an object"
+
+	// swaggerInitializerFormat initializes the Swagger UI with the URL of the spec, it is served as a file so that no
+	// inline script is needed.
+	swaggerInitializerFormat = `window.onload = function () {
+  window.ui = SwaggerUIBundle({url: %q, dom_id: "#swagger-ui", deepLinking: true});
+};
+`
+)
+
+var (
+	swaggerIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Swagger UI</title>
+  <link rel="stylesheet" href="{{ .UIURL }}/swagger-ui.css"{{ with .UICSSIntegrity }} integrity="{{ . }}"{{ end }} crossorigin="anonymous">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{ .UIURL }}/swagger-ui-bundle.js"{{ with .UIJSIntegrity }} integrity="{{ . }}"{{ end }} crossorigin="anonymous"></script>
+<script src="swagger-initializer.js"></script>
+</body>
+</html>
+`))
+)
+
+type (
+	// SwaggerConfig represents the configuration of the Swagger UI and spec endpoints.
+	SwaggerConfig struct {
+		Spec           []byte
+		SpecFile       string
+		SpecFS         fs.FS
+		ServerConfig   *config.ServerConfig
+		ApiBasePath    string
+		UIURL          string
+		UIFS           fs.FS
+		UICSSIntegrity string
+		UIJSIntegrity  string
+		Disabled       bool
+	}
+
+	// SwaggerOption is an option that can be used to configure the Swagger UI and spec endpoints.
+	SwaggerOption func(config *SwaggerConfig)
+)
+
+// WithSwaggerSpec is a RegisterSwagger option that defines the OpenAPI spec in JSON or YAML format,
+// for example a spec that is embedded with go:embed.
+func WithSwaggerSpec(spec []byte) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.Spec = spec
+	}
+}
+
+// WithSwaggerSpecFile is a RegisterSwagger option that reads the OpenAPI spec in JSON or YAML format from a file.
+func WithSwaggerSpecFile(filePath string) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.SpecFile = filePath
+	}
+}
+
+// WithSwaggerSpecFS is a RegisterSwagger option that reads the OpenAPI spec in JSON or YAML format from a file
+// in the file system, for example an embed.FS.
+func WithSwaggerSpecFS(fsys fs.FS, name string) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.SpecFS = fsys
+		config.SpecFile = name
+	}
+}
+
+// WithSwaggerServer is a RegisterSwagger option that sets the server of the spec to the url of the server,
+// see GetServerURL, and logs the docs url. The endpoints are not registered if ServerConfig.SwaggerDisabled is set.
+func WithSwaggerServer(sc *config.ServerConfig, apiBasePath string) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.ServerConfig = sc
+		config.ApiBasePath = apiBasePath
+		if sc != nil && sc.SwaggerDisabled {
+			config.Disabled = true
+		}
+	}
+}
+
+// WithSwaggerUIURL is a RegisterSwagger option that defines the location the Swagger UI assets are loaded from.
+// Default is DefaultSwaggerUIURL.
+func WithSwaggerUIURL(url string) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.UIURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithSwaggerUIIntegrity is a RegisterSwagger option that defines the subresource integrity hashes of the
+// swagger-ui.css and swagger-ui-bundle.js assets, for example "sha384-...". The browser refuses assets that do not
+// match the hashes.
+func WithSwaggerUIIntegrity(cssIntegrity, jsIntegrity string) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.UICSSIntegrity = cssIntegrity
+		config.UIJSIntegrity = jsIntegrity
+	}
+}
+
+// WithSwaggerUIFS is a RegisterSwagger option that serves the Swagger UI assets from the file system, for example
+// the dist directory of the swagger-ui-dist package that is embedded with go:embed. The file system must contain
+// swagger-ui.css and swagger-ui-bundle.js. The assets are served next to index.html instead of being loaded from
+// the UI url.
+func WithSwaggerUIFS(fsys fs.FS) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.UIFS = fsys
+	}
+}
+
+// WithSwaggerDisabled is a RegisterSwagger option that disables the endpoints, for example in production.
+func WithSwaggerDisabled(disabled bool) SwaggerOption {
+	return func(config *SwaggerConfig) {
+		config.Disabled = disabled
+	}
+}
+
+// RegisterSwagger registers the Swagger UI and the OpenAPI spec on the SwaggerPath of the routes.
+// The UI is served on /swagger/index.html and the spec is served in JSON format on /swagger/doc.json, see
+// SwaggerSpecPathFormat.
+// The page does not contain inline scripts, the UI is initialized by /swagger/swagger-initializer.js.
+// The routes should be the router group of the api base path that is passed to WithSwaggerServer.
+// An error is returned if the spec is missing or cannot be parsed.
+func RegisterSwagger(r gin.IRoutes, opts ...SwaggerOption) *errors.Error {
+	config := &SwaggerConfig{UIURL: DefaultSwaggerUIURL}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Disabled {
+		logger.Info(swaggerDisabledMsg)
+		return nil
+	}
+
+	spec, err := config.loadSpec()
+	if err != nil {
+		return err
+	}
+	if config.UIFS != nil {
+		config.UIURL = "."
+	}
+	var index bytes.Buffer
+	if err := swaggerIndexTemplate.Execute(&index, config); err != nil {
+		return errors.InternalServerError(err.Error())
+	}
+	var basePath string
+	if group, ok := r.(interface{ BasePath() string }); ok {
+		basePath = strings.TrimSuffix(group.BasePath(), "/")
+	}
+	initializer := fmt.Sprintf(swaggerInitializerFormat, fmt.Sprintf(SwaggerSpecPathFormat, basePath))
+
+	r.GET(SwaggerPath, func(ctx *gin.Context) {
+		switch ctx.Param("any") {
+		case swaggerSpecFile:
+			ctx.Data(http.StatusOK, ApplicationJsonMIMEType, spec)
+		case swaggerIndexFile:
+			ctx.Data(http.StatusOK, "text/html; charset=utf-8", index.Bytes())
+		case swaggerInitializerFile:
+			ctx.Data(http.StatusOK, "text/javascript; charset=utf-8", []byte(initializer))
+		case "", "/":
+			ctx.Redirect(http.StatusMovedPermanently, strings.TrimSuffix(ctx.Request.URL.Path, "/")+swaggerIndexFile)
+		default:
+			config.serveUIAsset(ctx, strings.TrimPrefix(ctx.Param("any"), "/"))
+		}
+	})
+	if config.ServerConfig != nil {
+		logger.Infof(ServerAPIDocsMsg, fmt.Sprintf(SwaggerUriPathFormat, GetServerURL(config.ServerConfig, config.ApiBasePath)))
+	}
+	return nil
+}
+
+// serveUIAsset serves the asset from the UI file system, if it is configured and contains the asset.
+func (config *SwaggerConfig) serveUIAsset(ctx *gin.Context, name string) {
+	if config.UIFS == nil || !fs.ValidPath(name) {
+		NoRoute(ctx)
+		return
+	}
+	if info, err := fs.Stat(config.UIFS, name); err != nil || info.IsDir() {
+		NoRoute(ctx)
+		return
+	}
+	ctx.FileFromFS(name, http.FS(config.UIFS))
+}
+
+// loadSpec reads the spec, sets the server of the spec if the server is configured and returns the spec in JSON format.
+func (config *SwaggerConfig) loadSpec() ([]byte, *errors.Error) {
+	data := config.Spec
+	if data == nil && config.SpecFile != "" {
+		var cErr *errors.Error
+		if config.SpecFS != nil {
+			var err error
+			if data, err = fs.ReadFile(config.SpecFS, config.SpecFile); err != nil {
+				cErr = errors.Newf(errors.ErrCodeFileReadError, 0, errors.ErrMsg[errors.ErrCodeFileReadError],
+					config.SpecFile, err.Error())
+			}
+		} else {
+			data, cErr = fileutil.ReadFile(config.SpecFile)
+		}
+		if cErr != nil {
+			return nil, cErr
+		}
+	}
+	if len(data) == 0 {
+		return nil, errors.Newf(errors.ErrCodeMissingMandatoryConfiguration, 0,
+			errors.ErrMsg[errors.ErrCodeMissingMandatoryConfiguration], swaggerSpecMissing)
+	}
+
+	spec, err := parseSpec(data)
+	if err != nil {
+		return nil, err
+	}
+	if config.ServerConfig != nil {
+		setSpecServer(spec, GetServerURL(config.ServerConfig, config.ApiBasePath))
+	}
+	out, mErr := json.Marshal(spec)
+	if mErr != nil {
+		return nil, errors.Newf(errors.ErrCodeJSONMarshalError, 0, errors.ErrMsg[errors.ErrCodeJSONMarshalError], mErr.Error())
+	}
+	return out, nil
+}
+
+// parseSpec parses a spec in JSON or YAML format.
+func parseSpec(data []byte) (map[string]any, *errors.Error) {
+	spec := make(map[string]any)
+	if err := json.Unmarshal(data, &spec); err == nil {
+		return spec, nil
+	}
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, errors.Newf(errors.ErrCodeYAMLUnmarshalError, 0, errors.ErrMsg[errors.ErrCodeYAMLUnmarshalError], err.Error())
+	}
+	spec, ok := normalizeYAML(v).(map[string]any)
+	if !ok {
+		return nil, errors.Newf(errors.ErrCodeYAMLUnmarshalError, 0, errors.ErrMsg[errors.ErrCodeYAMLUnmarshalError],
+			swaggerSpecInvalid)
+	}
+	return spec, nil
+}
+
+// normalizeYAML converts the maps of a decoded YAML document to maps with string keys, so it can be encoded as JSON.
+// YAML allows non string keys, for example the status codes of the responses of an operation.
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, e := range t {
+			t[k] = normalizeYAML(e)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, e := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(e)
+		}
+		return m
+	case []any:
+		for i, e := range t {
+			t[i] = normalizeYAML(e)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// setSpecServer sets the server of the spec. OpenAPI 3 specs get a single servers entry,
+// Swagger 2.0 specs get the host, base path and scheme of the server url.
+func setSpecServer(spec map[string]any, serverURL *url.URL) {
+	if _, ok := spec["swagger"]; !ok {
+		spec["servers"] = []map[string]any{{"url": serverURL.String()}}
+		return
+	}
+	spec["schemes"] = []string{serverURL.Scheme}
+	spec["host"] = serverURL.Host
+	spec["basePath"] = "/" + strings.TrimPrefix(serverURL.Path, "/")
+}
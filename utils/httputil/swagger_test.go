@@ -0,0 +1,168 @@
+package httputil
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/atselvan/go-utils/utils/config"
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testOpenApiSpecFile = "testdata/openapi.yaml"
+	testSwaggerSpecFile = "testdata/swagger.json"
+)
+
+func serveSwagger(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func readSpec(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	spec := make(map[string]any)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	return spec
+}
+
+func TestRegisterSwagger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sc := &config.ServerConfig{Protocol: "https", Host: "company.com", Port: "8080"}
+
+	t.Run("openapi yaml file", func(t *testing.T) {
+		r := gin.New()
+		api := r.Group("/api")
+		err := RegisterSwagger(api, WithSwaggerSpecFile(testOpenApiSpecFile), WithSwaggerServer(sc, "/api"))
+		assert.Nil(t, err)
+
+		w := serveSwagger(r, "/api/swagger/doc.json")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, ApplicationJsonMIMEType, w.Header().Get(ContentTypeHeaderKey))
+		spec := readSpec(t, w)
+		assert.Equal(t, []any{map[string]any{"url": "https://company.com/api"}}, spec["servers"])
+		responses := spec["paths"].(map[string]any)["/users/{id}"].(map[string]any)["get"].(map[string]any)["responses"]
+		assert.Contains(t, responses, "200")
+
+		w = serveSwagger(r, "/api/swagger/index.html")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `<script src="`+DefaultSwaggerUIURL+`/swagger-ui-bundle.js" crossorigin="anonymous">`)
+		assert.Contains(t, w.Body.String(), `<script src="swagger-initializer.js">`)
+		assert.NotContains(t, w.Body.String(), "SwaggerUIBundle(")
+
+		w = serveSwagger(r, "/api/swagger/swagger-initializer.js")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `url: "/api/swagger/doc.json"`)
+
+		w = serveSwagger(r, "/api/swagger/")
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/api/swagger/index.html", w.Header().Get("Location"))
+
+		w = serveSwagger(r, "/api/swagger/unknown")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("swagger json fs", func(t *testing.T) {
+		r := gin.New()
+		sc := &config.ServerConfig{Protocol: "http", Host: "localhost", Port: "8080"}
+		err := RegisterSwagger(r, WithSwaggerSpecFS(os.DirFS("testdata"), "swagger.json"),
+			WithSwaggerServer(sc, "/v1"), WithSwaggerUIURL("https://cdn.company.com/swagger-ui/"))
+		assert.Nil(t, err)
+
+		spec := readSpec(t, serveSwagger(r, "/swagger/doc.json"))
+		assert.Equal(t, []any{"http"}, spec["schemes"])
+		assert.Contains(t, serveSwagger(r, "/swagger/swagger-initializer.js").Body.String(), `url: "/swagger/doc.json"`)
+		assert.Equal(t, "localhost:8080", spec["host"])
+		assert.Equal(t, "/v1", spec["basePath"])
+		assert.NotContains(t, spec, "servers")
+
+		w := serveSwagger(r, "/swagger/index.html")
+		assert.Contains(t, w.Body.String(), "https://cdn.company.com/swagger-ui/swagger-ui.css")
+	})
+
+	t.Run("ui integrity", func(t *testing.T) {
+		r := gin.New()
+		err := RegisterSwagger(r, WithSwaggerSpecFile(testOpenApiSpecFile), WithSwaggerUIIntegrity("sha384-css", "sha384-js"))
+		assert.Nil(t, err)
+
+		w := serveSwagger(r, "/swagger/index.html")
+		assert.Contains(t, w.Body.String(), `swagger-ui.css" integrity="sha384-css" crossorigin="anonymous">`)
+		assert.Contains(t, w.Body.String(), `swagger-ui-bundle.js" integrity="sha384-js" crossorigin="anonymous">`)
+	})
+
+	t.Run("ui fs", func(t *testing.T) {
+		r := gin.New()
+		ui := fstest.MapFS{
+			"swagger-ui.css":       {Data: []byte("body {}")},
+			"swagger-ui-bundle.js": {Data: []byte("var SwaggerUIBundle;")},
+			"assets":               {Mode: fs.ModeDir},
+		}
+		err := RegisterSwagger(r, WithSwaggerSpecFile(testOpenApiSpecFile), WithSwaggerUIFS(ui))
+		assert.Nil(t, err)
+
+		w := serveSwagger(r, "/swagger/index.html")
+		assert.Contains(t, w.Body.String(), `<script src="./swagger-ui-bundle.js"`)
+		assert.NotContains(t, w.Body.String(), DefaultSwaggerUIURL)
+
+		w = serveSwagger(r, "/swagger/swagger-ui-bundle.js")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "var SwaggerUIBundle;", w.Body.String())
+		assert.Equal(t, "body {}", serveSwagger(r, "/swagger/swagger-ui.css").Body.String())
+
+		assert.Equal(t, http.StatusNotFound, serveSwagger(r, "/swagger/missing.js").Code)
+		assert.Equal(t, http.StatusNotFound, serveSwagger(r, "/swagger/assets").Code)
+		assert.Equal(t, http.StatusNotFound, serveSwagger(r, "/swagger/../swagger-ui.css").Code)
+	})
+
+	t.Run("spec without server", func(t *testing.T) {
+		r := gin.New()
+		data, _ := os.ReadFile(testSwaggerSpecFile)
+		err := RegisterSwagger(r, WithSwaggerSpec(data))
+		assert.Nil(t, err)
+
+		spec := readSpec(t, serveSwagger(r, "/swagger/doc.json"))
+		assert.Equal(t, "example.com", spec["host"])
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		r := gin.New()
+		sc := &config.ServerConfig{Protocol: "https", Host: "company.com", SwaggerDisabled: true}
+		err := RegisterSwagger(r, WithSwaggerServer(sc, "/api"))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, serveSwagger(r, "/swagger/doc.json").Code)
+
+		r = gin.New()
+		err = RegisterSwagger(r, WithSwaggerSpecFile(testOpenApiSpecFile), WithSwaggerDisabled(true))
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotFound, serveSwagger(r, "/swagger/index.html").Code)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := RegisterSwagger(gin.New())
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeMissingMandatoryConfiguration, err.Code)
+
+		err = RegisterSwagger(gin.New(), WithSwaggerSpecFile("testdata/missing.yaml"))
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeFileReadError, err.Code)
+
+		err = RegisterSwagger(gin.New(), WithSwaggerSpecFS(os.DirFS("testdata"), "missing.yaml"))
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeFileReadError, err.Code)
+
+		err = RegisterSwagger(gin.New(), WithSwaggerSpec([]byte("openapi: [3.0")))
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeYAMLUnmarshalError, err.Code)
+
+		err = RegisterSwagger(gin.New(), WithSwaggerSpec([]byte("- openapi")))
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeYAMLUnmarshalError, err.Code)
+	})
+}
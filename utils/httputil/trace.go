@@ -0,0 +1,118 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"github.com/atselvan/go-utils/utils/trace"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	TraceParentHeaderKey = "traceparent"
+	TraceIdContextKey    = "traceId"
+)
+
+var (
+	traceIdRegex     = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,128}$`)
+	traceParentRegex = regexp.MustCompile(`^[0-9a-f]{2}-([0-9a-f]{32})-[0-9a-f]{16}-[0-9a-f]{2}$`)
+)
+
+type (
+	// TraceIdConfig represents the configuration of the TraceId middleware.
+	TraceIdConfig struct {
+		Strict      bool
+		TraceParent bool
+	}
+
+	// TraceIdOption is an option that can be used to configure the TraceId middleware.
+	TraceIdOption func(config *TraceIdConfig)
+)
+
+// WithStrictTraceId is a TraceId option that rejects requests that do not have a valid trace id
+// with a ErrCodeTraceIdMissing error instead of generating a new trace id.
+func WithStrictTraceId() TraceIdOption {
+	return func(config *TraceIdConfig) {
+		config.Strict = true
+	}
+}
+
+// WithTraceParent is a TraceId option that uses the trace id of the W3C traceparent header
+// if the request does not have a valid Trace-Id header.
+func WithTraceParent() TraceIdOption {
+	return func(config *TraceIdConfig) {
+		config.TraceParent = true
+	}
+}
+
+// TraceId is a gin middleware that makes sure every request has a trace id.
+// A valid incoming Trace-Id header is kept and a new trace id is only generated if it is absent.
+// The trace id is set on the request header, stored in the gin context and the request context
+// and echoed in the Trace-Id response header.
+func TraceId(opts ...TraceIdOption) gin.HandlerFunc {
+	config := new(TraceIdConfig)
+	for _, opt := range opts {
+		opt(config)
+	}
+	return func(ctx *gin.Context) {
+		traceId := config.incomingTraceId(ctx.Request)
+		if traceId == "" {
+			if config.Strict {
+				ctx.Request.Header.Del(TraceIDHeaderKey)
+				AbortWithError(ctx, errors.New(
+					errors.ErrCodeTraceIdMissing,
+					http.StatusBadRequest,
+					errors.ErrMsg[errors.ErrCodeTraceIdMissing],
+				))
+				return
+			}
+			traceId = uuid.NewString()
+		}
+		ctx.Request.Header.Set(TraceIDHeaderKey, traceId)
+		ctx.Request = ctx.Request.WithContext(ContextWithTraceId(ctx.Request.Context(), traceId))
+		ctx.Set(TraceIdContextKey, traceId)
+		ctx.Header(TraceIDHeaderKey, traceId)
+		ctx.Next()
+	}
+}
+
+// incomingTraceId returns the valid trace id of the request or an empty string if the request has no valid trace id.
+func (config *TraceIdConfig) incomingTraceId(r *http.Request) string {
+	if traceId := strings.TrimSpace(r.Header.Get(TraceIDHeaderKey)); traceIdRegex.MatchString(traceId) {
+		return traceId
+	}
+	if config.TraceParent {
+		if m := traceParentRegex.FindStringSubmatch(strings.TrimSpace(r.Header.Get(TraceParentHeaderKey))); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// GetTraceId returns the trace id of the request.
+// The trace id that is stored in the gin context by the TraceId middleware is returned if it is set,
+// otherwise the value of the Trace-Id request header is returned.
+func GetTraceId(ctx *gin.Context) string {
+	if traceId := ctx.GetString(TraceIdContextKey); traceId != "" {
+		return traceId
+	}
+	if ctx.Request == nil {
+		return ""
+	}
+	return ctx.GetHeader(TraceIDHeaderKey)
+}
+
+// ContextWithTraceId returns a copy of the context that contains the trace id, see trace.NewContext.
+func ContextWithTraceId(ctx context.Context, traceId string) context.Context {
+	return trace.NewContext(ctx, traceId)
+}
+
+// TraceIdFromContext returns the trace id that is stored in the context or an empty string if it is not set,
+// see trace.FromContext.
+func TraceIdFromContext(ctx context.Context) string {
+	return trace.FromContext(ctx)
+}
@@ -0,0 +1,115 @@
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTraceId     = "967ed3d6-33ce-4091-943d-b3a6f8b591be"
+	testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+)
+
+var (
+	traceIdMissingResponse = `{"errors":[{"code":"TRACE_ID_MISSING","status":400,"message":"Trace-Id header must be set","traceId":""}]}`
+)
+
+func setupTraceRouter(opts ...TraceIdOption) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(TraceId(opts...))
+	r.GET("/trace", func(ctx *gin.Context) {
+		if GetTraceId(ctx) != TraceIdFromContext(ctx.Request.Context()) {
+			ctx.Status(http.StatusInternalServerError)
+			return
+		}
+		ctx.String(http.StatusOK, GetTraceId(ctx))
+	})
+	return r
+}
+
+func TestTraceId(t *testing.T) {
+	t.Run("generate", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		setupTraceRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, err := uuid.Parse(w.Body.String())
+		assert.NoError(t, err)
+		assert.Equal(t, w.Body.String(), w.Header().Get(TraceIDHeaderKey))
+		assert.Equal(t, w.Body.String(), req.Header.Get(TraceIDHeaderKey))
+	})
+
+	t.Run("keep incoming", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(TraceIDHeaderKey, testTraceId)
+		setupTraceRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testTraceId, w.Body.String())
+		assert.Equal(t, testTraceId, w.Header().Get(TraceIDHeaderKey))
+	})
+
+	t.Run("replace invalid incoming", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(TraceIDHeaderKey, "invalid trace id\n")
+		setupTraceRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		_, err := uuid.Parse(w.Body.String())
+		assert.NoError(t, err)
+	})
+
+	t.Run("traceparent", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(TraceParentHeaderKey, testTraceParent)
+		setupTraceRouter(WithTraceParent()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String())
+	})
+
+	t.Run("traceparent disabled", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(TraceParentHeaderKey, testTraceParent)
+		setupTraceRouter().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Body.String())
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		setupTraceRouter(WithStrictTraceId()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, traceIdMissingResponse, w.Body.String())
+	})
+
+	t.Run("strict with trace id", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/trace", nil)
+		req.Header.Set(TraceIDHeaderKey, testTraceId)
+		setupTraceRouter(WithStrictTraceId()).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, testTraceId, w.Body.String())
+	})
+}
+
+func TestTraceIdFromContext(t *testing.T) {
+	assert.Equal(t, "", TraceIdFromContext(context.Background()))
+	assert.Equal(t, testTraceId, TraceIdFromContext(ContextWithTraceId(context.Background(), testTraceId)))
+}
@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/dateutil"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	FieldStatus       = "status"
+	FieldMethod       = "method"
+	FieldPath         = "path"
+	FieldRoute        = "route"
+	FieldQuery        = "query"
+	FieldIP           = "ip"
+	FieldUserAgent    = "user-agent"
+	FieldLatency      = "latency"
+	FieldTraceId      = "trace-id"
+	FieldSpanId       = "span-id"
+	FieldRequestSize  = "request-size"
+	FieldResponseSize = "response-size"
+	FieldUser         = "user"
+	FieldErrors       = "errors"
+
+	defaultUserKey       = "username"
+	defaultTraceIdHeader = "Trace-Id"
+)
+
+var (
+	// DefaultAccessLogFields are the fields that are logged by GinZap when no custom fields are provided.
+	DefaultAccessLogFields = []string{
+		FieldStatus,
+		FieldMethod,
+		FieldPath,
+		FieldRoute,
+		FieldQuery,
+		FieldIP,
+		FieldUserAgent,
+		FieldLatency,
+		FieldTraceId,
+		FieldSpanId,
+		FieldRequestSize,
+		FieldResponseSize,
+		FieldUser,
+		FieldErrors,
+	}
+)
+
+type (
+	// GinZapConfig represents the configuration of the GinZap middleware.
+	GinZapConfig struct {
+		SkipPaths     []string
+		ErrorsOnly    bool
+		SlowThreshold time.Duration
+		Fields        []string
+		UserKey       string
+	}
+
+	// GinZapOption is an option that can be used to configure the GinZap middleware.
+	GinZapOption func(config *GinZapConfig)
+)
+
+// WithSkipPaths is a GinZap option that can be used to define paths for which requests are not logged.
+func WithSkipPaths(paths ...string) GinZapOption {
+	return func(config *GinZapConfig) {
+		config.SkipPaths = append(config.SkipPaths, paths...)
+	}
+}
+
+// WithErrorsOnly is a GinZap option that can be used to only log requests that have errors
+// or that resulted in a status code of 400 or higher.
+func WithErrorsOnly() GinZapOption {
+	return func(config *GinZapConfig) {
+		config.ErrorsOnly = true
+	}
+}
+
+// WithSlowThreshold is a GinZap option that can be used to only log requests
+// that took longer than the threshold.
+// If combined with WithErrorsOnly requests are logged if they have errors or if they are slow.
+func WithSlowThreshold(threshold time.Duration) GinZapOption {
+	return func(config *GinZapConfig) {
+		config.SlowThreshold = threshold
+	}
+}
+
+// WithFields is a GinZap option that can be used to define which fields are logged.
+// Default fields are DefaultAccessLogFields.
+func WithFields(fields ...string) GinZapOption {
+	return func(config *GinZapConfig) {
+		config.Fields = fields
+	}
+}
+
+// WithUserKey is a GinZap option that can be used to define the gin context key from which the user is read.
+// Default key is username, which is the key that is set by the basic authentication middleware.
+func WithUserKey(key string) GinZapOption {
+	return func(config *GinZapConfig) {
+		config.UserKey = key
+	}
+}
+
+// GinZap returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap.
+// Every request is logged with a single access log entry. The level of the entry is chosen by the status code:
+//
+//	5xx: zap.ErrorLevel
+//	4xx: zap.WarnLevel
+//	others: zap.InfoLevel, or zap.ErrorLevel if the request has errors
+//
+// Requests that are not logged because of the GinZapOption filters are counted in the suppressed log summary.
+func GinZap(opts ...GinZapOption) gin.HandlerFunc {
+	config := &GinZapConfig{
+		Fields:  DefaultAccessLogFields,
+		UserKey: defaultUserKey,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	skipPaths := make(map[string]bool, len(config.SkipPaths))
+	for _, path := range config.SkipPaths {
+		skipPaths[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := dateutil.Now()
+		path := c.Request.URL.Path
+		query := redactor.Load().query(c.Request.URL.RawQuery)
+		c.Next()
+		end := dateutil.Now()
+		latency := end.Sub(start)
+
+		if skipPaths[path] {
+			return
+		}
+		if !config.shouldLog(c, latency) {
+			countFiltered()
+			return
+		}
+
+		fields := make([]zapcore.Field, 0, len(config.Fields))
+		for _, name := range config.Fields {
+			switch name {
+			case FieldStatus:
+				fields = append(fields, zap.Int(name, c.Writer.Status()))
+			case FieldMethod:
+				fields = append(fields, zap.String(name, c.Request.Method))
+			case FieldPath:
+				fields = append(fields, zap.String(name, path))
+			case FieldRoute:
+				fields = append(fields, zap.String(name, c.FullPath()))
+			case FieldQuery:
+				fields = append(fields, zap.String(name, query))
+			case FieldIP:
+				fields = append(fields, zap.String(name, c.ClientIP()))
+			case FieldUserAgent:
+				fields = append(fields, zap.String(name, c.Request.UserAgent()))
+			case FieldLatency:
+				fields = append(fields, millis(name, latency))
+			case FieldTraceId:
+				fields = append(fields, zap.String(name, c.GetHeader(defaultTraceIdHeader)))
+			case FieldSpanId:
+				if sc := trace.SpanContextFromContext(c.Request.Context()); sc.HasSpanID() {
+					fields = append(fields, zap.String(name, sc.SpanID().String()))
+				}
+			case FieldRequestSize:
+				fields = append(fields, zap.Int64(name, requestSize(c.Request)))
+			case FieldResponseSize:
+				fields = append(fields, zap.Int(name, responseSize(c.Writer)))
+			case FieldUser:
+				fields = append(fields, zap.String(name, c.GetString(config.UserKey)))
+			case FieldErrors:
+				if len(c.Errors) > 0 {
+					fields = append(fields, zap.Strings(name, c.Errors.Errors()))
+				}
+			}
+		}
+
+		if ce := logger.WithOptions(zap.AddCallerSkip(1)).Check(accessLogLevel(c), path); ce != nil {
+			ce.Write(fields...)
+		}
+	}
+}
+
+// millis returns a field with the duration in milliseconds.
+func millis(key string, d time.Duration) zapcore.Field {
+	return zap.Float64(key, float64(d.Microseconds())/1000)
+}
+
+// shouldLog checks if a request should be logged based on the ErrorsOnly and SlowThreshold filters.
+func (config *GinZapConfig) shouldLog(c *gin.Context, latency time.Duration) bool {
+	if !config.ErrorsOnly && config.SlowThreshold <= 0 {
+		return true
+	}
+	if config.ErrorsOnly && (len(c.Errors) > 0 || c.Writer.Status() >= http.StatusBadRequest) {
+		return true
+	}
+	return config.SlowThreshold > 0 && latency >= config.SlowThreshold
+}
+
+// accessLogLevel returns the log level of the access log entry based on the status class of the response.
+func accessLogLevel(c *gin.Context) zapcore.Level {
+	switch status := c.Writer.Status(); {
+	case status >= http.StatusInternalServerError:
+		return zap.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return zap.WarnLevel
+	case len(c.Errors) > 0:
+		return zap.ErrorLevel
+	default:
+		return zap.InfoLevel
+	}
+}
+
+// requestSize returns the size of the request body or 0 if the size is unknown.
+func requestSize(r *http.Request) int64 {
+	if r.ContentLength < 0 {
+		return 0
+	}
+	return r.ContentLength
+}
+
+// responseSize returns the size of the response body or 0 if nothing was written.
+func responseSize(w gin.ResponseWriter) int {
+	if w.Size() < 0 {
+		return 0
+	}
+	return w.Size()
+}
@@ -0,0 +1,271 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestGinZap(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	r := newRouter()
+
+	apiPath := "/test"
+	msg := "some message"
+	r.GET(apiPath, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, msg)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, apiPath, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert sink contents
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, "\"level\":\"info\""))
+	assert.True(t, strings.Contains(output, "\"path\":\"/test\""))
+	assert.True(t, strings.Contains(output, "\"status\":200"))
+}
+
+func TestGinZapError(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	r := newRouter()
+
+	apiPath := "/test"
+	msg := "some message"
+	r.GET(apiPath, func(ctx *gin.Context) {
+		ctx.Errors = append(ctx.Errors, &gin.Error{
+			Err: errors.New(""),
+		})
+		ctx.JSON(http.StatusOK, msg)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, apiPath, nil)
+	r.ServeHTTP(w, req)
+
+	// Assert sink contents
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, "\"level\":\"error\""))
+	assert.True(t, strings.Contains(output, "\"caller\":\"gin"))
+}
+
+func TestGinZapOptions(t *testing.T) {
+	newFilteredRouter := func(opts ...GinZapOption) *gin.Engine {
+		gin.SetMode(gin.ReleaseMode)
+		r := gin.New()
+		r.Use(GinZap(opts...))
+		r.GET("/health", func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+		r.GET("/ok", func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+		r.GET("/bad", func(ctx *gin.Context) {
+			ctx.Status(http.StatusBadRequest)
+		})
+		r.GET("/slow", func(ctx *gin.Context) {
+			time.Sleep(20 * time.Millisecond)
+			ctx.Status(http.StatusOK)
+		})
+		return r
+	}
+	serve := func(r *gin.Engine, path string) {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("skip paths", func(t *testing.T) {
+		configureMockLogger(LevelInfo)
+		filteredCount.Store(0)
+		r := newFilteredRouter(WithSkipPaths("/health"))
+		serve(r, "/health")
+		serve(r, "/ok")
+
+		output := Sink.String()
+		assert.False(t, strings.Contains(output, `"path":"/health"`))
+		assert.True(t, strings.Contains(output, `"path":"/ok"`))
+		assert.Equal(t, int64(0), filteredCount.Load())
+	})
+
+	t.Run("errors only", func(t *testing.T) {
+		configureMockLogger(LevelInfo)
+		filteredCount.Store(0)
+		r := newFilteredRouter(WithErrorsOnly())
+		serve(r, "/ok")
+		serve(r, "/bad")
+
+		output := Sink.String()
+		assert.False(t, strings.Contains(output, `"path":"/ok"`))
+		assert.True(t, strings.Contains(output, `"path":"/bad"`))
+		assert.Equal(t, int64(1), filteredCount.Load())
+	})
+
+	t.Run("slow threshold", func(t *testing.T) {
+		configureMockLogger(LevelInfo)
+		filteredCount.Store(0)
+		r := newFilteredRouter(WithSlowThreshold(10 * time.Millisecond))
+		serve(r, "/ok")
+		serve(r, "/slow")
+
+		output := Sink.String()
+		assert.False(t, strings.Contains(output, `"path":"/ok"`))
+		assert.True(t, strings.Contains(output, `"path":"/slow"`))
+		assert.Equal(t, int64(1), filteredCount.Load())
+	})
+
+	t.Run("errors only or slow", func(t *testing.T) {
+		configureMockLogger(LevelInfo)
+		r := newFilteredRouter(WithErrorsOnly(), WithSlowThreshold(10*time.Millisecond))
+		serve(r, "/ok")
+		serve(r, "/bad")
+		serve(r, "/slow")
+
+		output := Sink.String()
+		assert.False(t, strings.Contains(output, `"path":"/ok"`))
+		assert.True(t, strings.Contains(output, `"path":"/bad"`))
+		assert.True(t, strings.Contains(output, `"path":"/slow"`))
+	})
+}
+
+func TestGinZapAccessLog(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	r := newRouter()
+	r.POST("/users/:id", func(ctx *gin.Context) {
+		ctx.Set(defaultUserKey, "admin")
+		ctx.String(http.StatusCreated, "created")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/users/1", strings.NewReader(`{"name":"test"}`))
+	req.Header.Set(defaultTraceIdHeader, "trace")
+	r.ServeHTTP(w, req)
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, `"level":"info"`))
+	assert.True(t, strings.Contains(output, `"status":201`))
+	assert.True(t, strings.Contains(output, `"route":"/users/:id"`))
+	assert.True(t, strings.Contains(output, `"path":"/users/1"`))
+	assert.True(t, strings.Contains(output, `"trace-id":"trace"`))
+	assert.True(t, strings.Contains(output, `"request-size":15`))
+	assert.True(t, strings.Contains(output, `"response-size":7`))
+	assert.True(t, strings.Contains(output, `"user":"admin"`))
+	assert.True(t, strings.Contains(output, `"latency":`))
+	assert.False(t, strings.Contains(output, `"latency":"`))
+	assert.False(t, strings.Contains(output, `"errors"`))
+	assert.False(t, strings.Contains(output, `"span-id"`))
+}
+
+func TestGinZapSpanId(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	r := newRouter()
+	r.GET("/users/:id", func(ctx *gin.Context) {
+		spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+			SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		})
+		ctx.Request = ctx.Request.WithContext(trace.ContextWithSpanContext(ctx.Request.Context(), spanCtx))
+		ctx.String(http.StatusOK, "OK")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	r.ServeHTTP(w, req)
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, `"span-id":"00f067aa0ba902b7"`))
+}
+
+func TestGinZapLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		err    error
+		level  string
+	}{
+		{name: "success", status: http.StatusOK, level: "info"},
+		{name: "client error", status: http.StatusNotFound, level: "warn"},
+		{name: "server error", status: http.StatusBadGateway, level: "error"},
+		{name: "success with error", status: http.StatusOK, err: errors.New("failed"), level: "error"},
+		{name: "client error with error", status: http.StatusBadRequest, err: errors.New("invalid"), level: "warn"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configureMockLogger(LevelInfo)
+			r := newRouter()
+			r.GET("/test", func(ctx *gin.Context) {
+				if tt.err != nil {
+					_ = ctx.Error(tt.err)
+				}
+				ctx.Status(tt.status)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(defaultTraceIdHeader, "trace")
+			r.ServeHTTP(w, req)
+
+			output := Sink.String()
+			t.Logf("output = %s", output)
+
+			assert.Equal(t, 1, strings.Count(output, "\n"))
+			assert.True(t, strings.Contains(output, `"level":"`+tt.level+`"`))
+			assert.True(t, strings.Contains(output, `"trace-id":"trace"`))
+			if tt.err != nil {
+				assert.True(t, strings.Contains(output, `"errors":["`+tt.err.Error()+`"]`))
+			}
+		})
+	}
+}
+
+func TestGinZapWithFields(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(GinZap(WithFields(FieldStatus, FieldUser), WithUserKey("consumer")))
+	r.GET("/test", func(ctx *gin.Context) {
+		ctx.Set("consumer", "service")
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/test", nil)
+	r.ServeHTTP(w, req)
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, `"status":200`))
+	assert.True(t, strings.Contains(output, `"user":"service"`))
+	assert.False(t, strings.Contains(output, `"method"`))
+	assert.False(t, strings.Contains(output, `"path"`))
+}
+
+func TestMillis(t *testing.T) {
+	assert.Equal(t, zap.Float64(FieldLatency, 1.5), millis(FieldLatency, 1500*time.Microsecond))
+	assert.Equal(t, zap.Float64(FieldLatency, 2000), millis(FieldLatency, 2*time.Second))
+}
+
+func newRouter() *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(GinZap())
+	r.Use(gin.Recovery())
+	return r
+}
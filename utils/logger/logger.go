@@ -3,9 +3,8 @@ package logger
 import (
 	"fmt"
 	"log"
+	"time"
 
-	"github.com/atselvan/go-utils/utils/dateutil"
-	"github.com/gin-gonic/gin"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -14,14 +13,19 @@ import (
 var (
 	loggerConfig = getDefaultZapConfig()
 	logger       *zap.Logger
+	entryHooks   []func(zapcore.Entry) error
 	LevelInfo    = zap.InfoLevel.CapitalString()
 	LevelDebug   = zap.DebugLevel.CapitalString()
 )
 
 type (
 	Config struct {
-		Level       *zapcore.Level
-		OutputPaths []string
+		Level           *zapcore.Level
+		OutputPaths     []string
+		Redaction       *RedactionConfig
+		Sampling        *SamplingConfig
+		SummaryInterval *time.Duration
+		Hooks           []func(zapcore.Entry) error
 	}
 
 	Option func(config *Config)
@@ -47,9 +51,26 @@ func SetLogger(opts ...Option) {
 		loggerConfig.OutputPaths = config.OutputPaths
 	}
 
-	if logger, err = loggerConfig.Build(); err != nil {
+	if config.Redaction != nil {
+		redactor.Store(newRedactor(*config.Redaction))
+	}
+
+	if config.Sampling != nil {
+		setSampling(config.Sampling)
+	}
+
+	if config.SummaryInterval != nil {
+		setSummaryInterval(*config.SummaryInterval)
+	}
+
+	if config.Hooks != nil {
+		entryHooks = config.Hooks
+	}
+
+	if logger, err = buildLogger(loggerConfig); err != nil {
 		log.Fatalln("Unable to initialize logger: ", err)
 	}
+	startSummary(logger)
 }
 
 // WithLogLevel is an option that can be used to define a custom log level
@@ -74,12 +95,44 @@ func WithOutputPaths(outputPaths []string) Option {
 	}
 }
 
+// WithHooks is an option that can be used to define functions that are called for every entry that is logged,
+// for example to count the entries per level. The hooks replace the previously defined hooks,
+// calling WithHooks without hooks removes them.
+func WithHooks(hooks ...func(zapcore.Entry) error) Option {
+	return func(config *Config) {
+		config.Hooks = append([]func(zapcore.Entry) error{}, hooks...)
+	}
+}
+
 // SetLoggerWithConfig initializes the logger util with a custom configuration.
+// The current redaction configuration and hooks are applied to the logger.
 func SetLoggerWithConfig(config zap.Config) {
 	var err error
-	if logger, err = config.Build(); err != nil {
+	if logger, err = buildLogger(config); err != nil {
 		log.Fatalln("Unable to initialize logger: ", err)
 	}
+	startSummary(logger)
+}
+
+// buildLogger builds a logger with the config, the current redaction configuration and hooks.
+func buildLogger(config zap.Config) (*zap.Logger, error) {
+	return config.Build(zap.WrapCore(newRedactCore(redactor.Load())), zap.Hooks(entryHooks...))
+}
+
+// GetLogger returns the zap.Logger that is used by the logger util.
+func GetLogger() *zap.Logger {
+	return logger
+}
+
+// ReplaceLogger replaces the zap.Logger that is used by the logger util and
+// returns a function that restores the previous logger.
+// The current redaction configuration is applied to the new logger.
+func ReplaceLogger(l *zap.Logger) func() {
+	previous := logger
+	logger = l.WithOptions(zap.WrapCore(newRedactCore(redactor.Load())))
+	return func() {
+		logger = previous
+	}
 }
 
 // getDefaultZapConfig returns the default logger config with the desired log level.
@@ -166,46 +219,17 @@ func Panicf(format string, a ...any) {
 	logger.WithOptions(zap.AddCallerSkip(1)).Panic(fmt.Sprintf(format, a...))
 }
 
-// GinZap returns a gin.HandlerFunc (middleware) that logs requests using uber-go/zap.
-// Requests with errors are logged using zap.Error().
-// Requests without errors are logged using zap.Info().
-func GinZap() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		start := dateutil.Now()
-		path := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
-		c.Next()
-		end := dateutil.Now()
-		latency := end.Sub(start)
-
-		if len(c.Errors) > 0 {
-			for _, e := range c.Errors.Errors() {
-				logger.WithOptions(zap.AddCallerSkip(1)).Error(e)
-			}
-		} else {
-			logger.Info(path,
-				zap.Int("status", c.Writer.Status()),
-				zap.String("method", c.Request.Method),
-				zap.String("path", path),
-				zap.String("query", query),
-				zap.String("ip", c.ClientIP()),
-				zap.String("user-agent", c.Request.UserAgent()),
-				zap.String("latency", latency.String()),
-				zap.String("trace-id", c.GetHeader("Trace-Id")),
-			)
-		}
-	}
-}
-
 // RestyDebugLogger prints debug logs for a http request based on the resty.Response.
+// Sensitive headers and body values are redacted, the request itself is not modified.
+//
+// Deprecated: use UseRestyLogger to log outbound calls with a single structured log entry.
 func RestyDebugLogger(resp *resty.Response) {
-	resp.Request.Header["Authorization"] = []string{}
-	Debug(fmt.Sprintf("Request: %v", resp.Request))
-	Debug(fmt.Sprintf("Request Url: %v", resp.Request.URL))
-	Debug(fmt.Sprintf("Request Header: %v", resp.Request.Header))
-	Debug(fmt.Sprintf("Request Body: %v", resp.Request.Body))
+	Debug(fmt.Sprintf("Request: %s %s", resp.Request.Method, redactor.Load().url(resp.Request.URL)))
+	Debug(fmt.Sprintf("Request Url: %v", redactor.Load().url(resp.Request.URL)))
+	Debug(fmt.Sprintf("Request Header: %v", redactor.Load().header(resp.Request.Header)))
+	Debug(fmt.Sprintf("Request Body: %v", redactor.Load().requestBody(resp.Request.Body)))
 	Debug(fmt.Sprintf("Response Status: %v", resp.Status()))
 	Debug(fmt.Sprintf("Response Status Code: %v", resp.StatusCode()))
-	Debug(fmt.Sprintf("Response Header: %v", resp.Header()))
-	Debug(fmt.Sprintf("Response Body: %v", resp.String()))
+	Debug(fmt.Sprintf("Response Header: %v", redactor.Load().header(resp.Header())))
+	Debug(fmt.Sprintf("Response Body: %v", redactor.Load().body(resp.Body())))
 }
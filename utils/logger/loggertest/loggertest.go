@@ -0,0 +1,120 @@
+package loggertest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Recorder captures the entries that are logged using the logger util.
+type Recorder struct {
+	t    testing.TB
+	logs *observer.ObservedLogs
+}
+
+// New replaces the logger of the logger util with a logger that captures all entries
+// for the duration of the test. The previous logger is restored when the test finishes.
+func New(t testing.TB) *Recorder {
+	core, logs := observer.New(zapcore.DebugLevel)
+	restore := logger.ReplaceLogger(zap.New(core, zap.AddCaller()))
+	t.Cleanup(restore)
+	return &Recorder{t: t, logs: logs}
+}
+
+// Entries returns all captured entries with their fields.
+func (r *Recorder) Entries() []observer.LoggedEntry {
+	return r.logs.All()
+}
+
+// FilterLevel returns the captured entries that were logged at the level.
+func (r *Recorder) FilterLevel(level zapcore.Level) []observer.LoggedEntry {
+	return r.logs.FilterLevelExact(level).All()
+}
+
+// FilterMessage returns the captured entries that were logged with the message.
+func (r *Recorder) FilterMessage(msg string) []observer.LoggedEntry {
+	return r.logs.FilterMessage(msg).All()
+}
+
+// Reset removes all captured entries.
+func (r *Recorder) Reset() {
+	r.logs.TakeAll()
+}
+
+// AssertLogged asserts that an entry was logged at the level with the message and all the fields.
+func (r *Recorder) AssertLogged(level zapcore.Level, msg string, fields ...zapcore.Field) bool {
+	r.t.Helper()
+	expected := encodeFields(fields)
+	for _, e := range r.logs.All() {
+		if e.Level == level && e.Message == msg && containsFields(e.ContextMap(), expected) {
+			return true
+		}
+	}
+	return assert.Fail(r.t, fmt.Sprintf("No entry logged at %s with message %q and fields %v", level.CapitalString(), msg, expected),
+		r.String())
+}
+
+// AssertLoggedWithField asserts that an entry was logged at the level with the field key and value.
+func (r *Recorder) AssertLoggedWithField(level zapcore.Level, key string, value any) bool {
+	r.t.Helper()
+	expected := map[string]any{key: value}
+	for _, e := range r.logs.All() {
+		if e.Level == level && containsFields(e.ContextMap(), expected) {
+			return true
+		}
+	}
+	return assert.Fail(r.t, fmt.Sprintf("No entry logged at %s with field %s=%v", level.CapitalString(), key, value),
+		r.String())
+}
+
+// AssertNotLogged asserts that no entry was logged at the level with the message.
+func (r *Recorder) AssertNotLogged(level zapcore.Level, msg string) bool {
+	r.t.Helper()
+	if entries := r.logs.FilterLevelExact(level).FilterMessage(msg).All(); len(entries) > 0 {
+		return assert.Fail(r.t, fmt.Sprintf("Entry logged at %s with message %q", level.CapitalString(), msg),
+			r.String())
+	}
+	return true
+}
+
+// AssertCount asserts the number of captured entries.
+func (r *Recorder) AssertCount(count int) bool {
+	r.t.Helper()
+	return assert.Equal(r.t, count, r.logs.Len(), r.String())
+}
+
+// String returns a readable representation of all captured entries.
+func (r *Recorder) String() string {
+	var sb strings.Builder
+	sb.WriteString("captured entries:")
+	for _, e := range r.logs.All() {
+		sb.WriteString(fmt.Sprintf("\n\t%s %q %v", e.Level.CapitalString(), e.Message, e.ContextMap()))
+	}
+	return sb.String()
+}
+
+// encodeFields encodes zap fields into a map in the same way as the captured entries.
+func encodeFields(fields []zapcore.Field) map[string]any {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return enc.Fields
+}
+
+// containsFields checks if all expected fields are present in the actual fields.
+func containsFields(actual, expected map[string]any) bool {
+	for k, v := range expected {
+		a, ok := actual[k]
+		if !ok || !assert.ObjectsAreEqualValues(v, a) {
+			return false
+		}
+	}
+	return true
+}
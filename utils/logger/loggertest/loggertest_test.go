@@ -0,0 +1,78 @@
+package loggertest
+
+import (
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockT records failures instead of failing the test.
+type mockT struct {
+	testing.TB
+	failed bool
+}
+
+func (m *mockT) Helper()               {}
+func (m *mockT) Errorf(string, ...any) { m.failed = true }
+func (m *mockT) Cleanup(func())        {}
+
+func TestNew(t *testing.T) {
+	previous := logger.GetLogger()
+
+	t.Run("capture", func(t *testing.T) {
+		rec := New(t)
+		assert.NotEqual(t, previous, logger.GetLogger())
+
+		logger.Warn("warn message", zap.String("trace-id", "trace"), zap.Int("status", 404))
+		logger.Debug("debug message")
+
+		entries := rec.Entries()
+		assert.Len(t, entries, 2)
+		assert.Len(t, rec.FilterLevel(zap.WarnLevel), 1)
+		assert.Len(t, rec.FilterMessage("debug message"), 1)
+		assert.Equal(t, "trace", entries[0].ContextMap()["trace-id"])
+		assert.Contains(t, entries[0].Caller.File, "loggertest_test.go")
+	})
+
+	assert.Equal(t, previous, logger.GetLogger())
+}
+
+func TestRecorder_Assertions(t *testing.T) {
+	rec := New(t)
+	logger.Warn("warn message", zap.String("trace-id", "trace"), zap.Int("status", 404))
+
+	t.Run("success", func(t *testing.T) {
+		assert.True(t, rec.AssertLogged(zap.WarnLevel, "warn message"))
+		assert.True(t, rec.AssertLogged(zap.WarnLevel, "warn message", zap.Int("status", 404)))
+		assert.True(t, rec.AssertLoggedWithField(zap.WarnLevel, "trace-id", "trace"))
+		assert.True(t, rec.AssertLoggedWithField(zap.WarnLevel, "status", 404))
+		assert.True(t, rec.AssertNotLogged(zap.ErrorLevel, "warn message"))
+		assert.True(t, rec.AssertCount(1))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		m := &mockT{TB: t}
+		failing := &Recorder{t: m, logs: rec.logs}
+
+		assert.False(t, failing.AssertLogged(zap.InfoLevel, "warn message"))
+		assert.False(t, failing.AssertLogged(zap.WarnLevel, "warn message", zap.Int("status", 500)))
+		assert.False(t, failing.AssertLoggedWithField(zap.WarnLevel, "trace-id", "other"))
+		assert.False(t, failing.AssertNotLogged(zap.WarnLevel, "warn message"))
+		assert.False(t, failing.AssertCount(2))
+		assert.True(t, m.failed)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		rec.Reset()
+		assert.Empty(t, rec.Entries())
+	})
+}
+
+func TestRecorder_Redaction(t *testing.T) {
+	rec := New(t)
+	logger.Info("login", zap.String("password", "secret"))
+
+	rec.AssertLoggedWithField(zap.InfoLevel, "password", logger.RedactedValue)
+}
@@ -0,0 +1,331 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// RedactedValue is the placeholder that replaces sensitive values in the log output.
+	RedactedValue = "[REDACTED]"
+)
+
+var (
+	redactor = newRedactorPointer(DefaultRedactionConfig())
+)
+
+type (
+	// RedactionConfig defines which values are masked before they are written to the log output.
+	// Header, body key and query parameter names are matched case-insensitively.
+	RedactionConfig struct {
+		Headers     []string
+		BodyKeys    []string
+		QueryParams []string
+		Patterns    []*regexp.Regexp
+	}
+
+	// fieldRedactor masks sensitive values based on a RedactionConfig.
+	fieldRedactor struct {
+		headers     map[string]bool
+		bodyKeys    map[string]bool
+		queryParams map[string]bool
+		patterns    []*regexp.Regexp
+	}
+
+	// redactCore is a zapcore.Core that redacts the message and fields of every entry
+	// before passing it on to the wrapped core.
+	redactCore struct {
+		zapcore.Core
+		r *fieldRedactor
+	}
+
+	// redactedEntry is a zapcore.Core that writes an entry that was checked by the wrapped core of a redactCore,
+	// after the message and fields are redacted.
+	redactedEntry struct {
+		zapcore.Core
+		ce *zapcore.CheckedEntry
+		r  *fieldRedactor
+	}
+)
+
+// DefaultRedactionConfig returns the redaction configuration that is used when no custom
+// configuration is provided.
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Headers: []string{
+			"Authorization",
+			"Proxy-Authorization",
+			"Cookie",
+			"Set-Cookie",
+			"Subject-Token",
+			"X-API-Key",
+		},
+		BodyKeys: []string{
+			"password",
+			"secret",
+			"token",
+			"access_token",
+			"refresh_token",
+			"client_secret",
+		},
+		QueryParams: []string{
+			"token",
+			"access_token",
+			"refresh_token",
+			"client_secret",
+			"password",
+			"api_key",
+		},
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\b(bearer|basic)\s+[a-z0-9\-._~+/]+=*`),
+		},
+	}
+}
+
+// WithRedaction is an option that can be used to define a custom redaction configuration.
+// The configuration replaces the DefaultRedactionConfig.
+func WithRedaction(redaction RedactionConfig) Option {
+	return func(config *Config) {
+		config.Redaction = &redaction
+	}
+}
+
+// RedactHeaders returns a copy of the headers in which the values of sensitive headers are redacted.
+// The input headers are not modified.
+func RedactHeaders(header http.Header) http.Header {
+	return redactor.Load().header(header)
+}
+
+// RedactQuery returns the raw query string in which the values of sensitive query parameters are redacted.
+func RedactQuery(rawQuery string) string {
+	return redactor.Load().query(rawQuery)
+}
+
+// RedactBody returns the body as a string in which sensitive values are redacted.
+// JSON bodies are redacted based on the configured body keys, other bodies based on the configured patterns.
+func RedactBody(body []byte) string {
+	return redactor.Load().body(body)
+}
+
+// RedactString returns the string in which all matches of the configured patterns are redacted.
+func RedactString(s string) string {
+	return redactor.Load().string(s)
+}
+
+// newRedactorPointer returns a pointer that can be swapped atomically to a fieldRedactor for the redaction configuration.
+func newRedactorPointer(config RedactionConfig) *atomic.Pointer[fieldRedactor] {
+	p := new(atomic.Pointer[fieldRedactor])
+	p.Store(newRedactor(config))
+	return p
+}
+
+// newRedactor returns a fieldRedactor for the redaction configuration.
+func newRedactor(config RedactionConfig) *fieldRedactor {
+	return &fieldRedactor{
+		headers:     toLowerSet(config.Headers),
+		bodyKeys:    toLowerSet(config.BodyKeys),
+		queryParams: toLowerSet(config.QueryParams),
+		patterns:    config.Patterns,
+	}
+}
+
+// toLowerSet converts a slice of names into a set of lower case names.
+func toLowerSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(name)] = true
+	}
+	return set
+}
+
+// string redacts all matches of the configured patterns.
+func (r *fieldRedactor) string(s string) string {
+	for _, p := range r.patterns {
+		s = p.ReplaceAllString(s, RedactedValue)
+	}
+	return s
+}
+
+// isSensitiveKey checks if a field key matches any of the configured names.
+func (r *fieldRedactor) isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	return r.headers[key] || r.bodyKeys[key] || r.queryParams[key]
+}
+
+// header returns a redacted copy of the headers.
+func (r *fieldRedactor) header(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for k, v := range header {
+		if r.headers[strings.ToLower(k)] {
+			redacted[k] = []string{RedactedValue}
+			continue
+		}
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = r.string(v[i])
+		}
+		redacted[k] = values
+	}
+	return redacted
+}
+
+// query redacts the values of sensitive query parameters while keeping the order of the parameters.
+func (r *fieldRedactor) query(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if r.queryParams[strings.ToLower(key)] {
+			params[i] = key + "=" + RedactedValue
+		}
+	}
+	return r.string(strings.Join(params, "&"))
+}
+
+// url redacts the values of sensitive query parameters of a raw url.
+func (r *fieldRedactor) url(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return r.string(rawURL)
+	}
+	u.RawQuery = r.query(u.RawQuery)
+	return r.string(u.String())
+}
+
+// body redacts a JSON body based on the configured body keys and any other body based on the configured patterns.
+func (r *fieldRedactor) body(body []byte) string {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return r.string(string(body))
+	}
+	b, err := json.Marshal(r.value(v))
+	if err != nil {
+		return r.string(string(body))
+	}
+	return r.string(string(b))
+}
+
+// value walks a decoded JSON value and redacts the values of sensitive keys.
+func (r *fieldRedactor) value(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			if r.bodyKeys[strings.ToLower(k)] {
+				t[k] = RedactedValue
+			} else {
+				t[k] = r.value(val)
+			}
+		}
+	case []any:
+		for i := range t {
+			t[i] = r.value(t[i])
+		}
+	case string:
+		return r.string(t)
+	}
+	return v
+}
+
+// field redacts a single zap field.
+func (r *fieldRedactor) field(f zapcore.Field) zapcore.Field {
+	if r.isSensitiveKey(f.Key) {
+		return zap.String(f.Key, RedactedValue)
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = r.string(f.String)
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return zap.String(f.Key, r.string(err.Error()))
+		}
+	case zapcore.ReflectType:
+		b, err := json.Marshal(f.Interface)
+		if err != nil {
+			return f
+		}
+		return zap.Reflect(f.Key, json.RawMessage(r.body(b)))
+	}
+	return f
+}
+
+// fields redacts a list of zap fields.
+func (r *fieldRedactor) fields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i := range fields {
+		redacted[i] = r.field(fields[i])
+	}
+	return redacted
+}
+
+// requestBody formats a resty request body and redacts its sensitive values.
+func (r *fieldRedactor) requestBody(body any) string {
+	switch b := body.(type) {
+	case nil:
+		return fmt.Sprintf("%v", b)
+	case string:
+		return r.body([]byte(b))
+	case []byte:
+		return r.body(b)
+	default:
+		byt, err := json.Marshal(b)
+		if err != nil {
+			return r.string(fmt.Sprintf("%v", b))
+		}
+		return r.body(byt)
+	}
+}
+
+// newRedactCore wraps a zapcore.Core with a redactCore.
+func newRedactCore(r *fieldRedactor) func(zapcore.Core) zapcore.Core {
+	return func(core zapcore.Core) zapcore.Core {
+		return &redactCore{Core: core, r: r}
+	}
+}
+
+// With adds redacted structured context to the core.
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.fields(fields)), r: c.r}
+}
+
+// Check determines whether the supplied entry should be logged.
+// The wrapped core checks the entry, so that its own decisions, like the levels of the cores of a tee or sampling,
+// are respected. The entry that is checked by the wrapped core is only written after it is redacted.
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	if checked := c.Core.Check(ent, nil); checked != nil {
+		checked.ErrorOutput = zapcore.Lock(os.Stderr)
+		return ce.AddCore(ent, &redactedEntry{Core: c.Core, ce: checked, r: c.r})
+	}
+	return ce
+}
+
+// Write redacts the message and fields of the entry and writes it to the wrapped core.
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.r.string(ent.Message)
+	return c.Core.Write(ent, c.r.fields(fields))
+}
+
+// Write redacts the message and fields of the entry and writes it to the cores that accepted the entry.
+// Write errors of the cores are reported to stderr, like zap does by default.
+func (e *redactedEntry) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = e.r.string(ent.Message)
+	e.ce.Entry = ent
+	e.ce.Write(e.r.fields(fields)...)
+	return nil
+}
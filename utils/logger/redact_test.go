@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	header.Set("Subject-Token", "token")
+	header.Set("Accept", "application/json")
+
+	redacted := RedactHeaders(header)
+	assert.Equal(t, RedactedValue, redacted.Get("Authorization"))
+	assert.Equal(t, RedactedValue, redacted.Get("Subject-Token"))
+	assert.Equal(t, "application/json", redacted.Get("Accept"))
+	assert.Equal(t, "Basic YWRtaW46YWRtaW4=", header.Get("Authorization"))
+}
+
+func TestRedactQuery(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, "", RedactQuery(""))
+	})
+
+	t.Run("sensitive params", func(t *testing.T) {
+		assert.Equal(t, "page=1&access_token="+RedactedValue+"&Token="+RedactedValue,
+			RedactQuery("page=1&access_token=abc&Token=def"))
+	})
+}
+
+func TestRedactBody(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		body := RedactBody([]byte(`{"username":"admin","password":"admin","nested":[{"secret":"x"}]}`))
+		assert.Equal(t, `{"nested":[{"secret":"[REDACTED]"}],"password":"[REDACTED]","username":"admin"}`, body)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		assert.Equal(t, "auth "+RedactedValue, RedactBody([]byte("auth Bearer abc.def")))
+	})
+}
+
+func TestRedactString(t *testing.T) {
+	assert.Equal(t, "header: "+RedactedValue, RedactString("header: Basic YWRtaW46YWRtaW4="))
+}
+
+func TestWithRedaction(t *testing.T) {
+	defer SetLogger(WithRedaction(DefaultRedactionConfig()))
+
+	configureMockLogger(LevelInfo)
+	SetLogger(WithRedaction(RedactionConfig{
+		BodyKeys: []string{"pin"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}`)},
+	}))
+
+	Info("card 1234-5678",
+		zap.String("pin", "1234"),
+		zap.String("note", "card 1234-5678"),
+		zap.Error(errors.New("card 1234-5678")),
+		zap.Any("payload", map[string]string{"pin": "1234", "name": "test"}),
+	)
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.False(t, strings.Contains(output, "1234"))
+	assert.True(t, strings.Contains(output, `"pin":"[REDACTED]"`))
+	assert.True(t, strings.Contains(output, `"name":"test"`))
+	assert.True(t, strings.Contains(output, `"message":"card [REDACTED]"`))
+}
+
+func TestWithRedactionConcurrent(t *testing.T) {
+	defer SetLogger(WithRedaction(DefaultRedactionConfig()))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_ = RedactString("card 1234-5678")
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		SetLogger(WithRedaction(RedactionConfig{Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{4}-\d{4}`)}}))
+	}
+	<-done
+	assert.Equal(t, "card [REDACTED]", RedactString("card 1234-5678"))
+}
+
+func TestGinZapRedactsQuery(t *testing.T) {
+	configureMockLogger(LevelInfo)
+	r := newRouter()
+	r.GET("/test", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/test?page=1&token=abc", nil)
+	r.ServeHTTP(w, req)
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.True(t, strings.Contains(output, `"query":"page=1&token=[REDACTED]"`))
+}
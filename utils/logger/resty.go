@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	FieldURL             = "url"
+	FieldDuration        = "duration"
+	FieldAttempt         = "attempt"
+	FieldRequestHeaders  = "request-headers"
+	FieldRequestBody     = "request-body"
+	FieldResponseBody    = "response-body"
+	restyLogMsg          = "Outbound request"
+	truncatedSuffix      = "...(truncated)"
+	defaultMaxBodySize   = 1024
+	defaultRestyLogLevel = zap.DebugLevel
+)
+
+type (
+	// RestyLoggerConfig represents the configuration of the resty client logging hooks.
+	RestyLoggerConfig struct {
+		Level       zapcore.Level
+		MaxBodySize int
+	}
+
+	// RestyLoggerOption is an option that can be used to configure the resty client logging hooks.
+	RestyLoggerOption func(config *RestyLoggerConfig)
+)
+
+// WithRestyLogLevel is a RestyLoggerOption that can be used to define the level at which successful calls are logged.
+// Default level is zap.DebugLevel.
+func WithRestyLogLevel(level zapcore.Level) RestyLoggerOption {
+	return func(config *RestyLoggerConfig) {
+		config.Level = level
+	}
+}
+
+// WithMaxBodySize is a RestyLoggerOption that can be used to define the maximum number of bytes
+// of the request and response bodies that are logged. Bodies are not logged if the size is 0.
+// Default size is 1024.
+func WithMaxBodySize(size int) RestyLoggerOption {
+	return func(config *RestyLoggerConfig) {
+		config.MaxBodySize = size
+	}
+}
+
+// UseRestyLogger attaches hooks to the resty client that log a single structured entry per outbound call.
+// Calls that received a response are logged after the response was received, with one entry per attempt.
+// Calls that failed without a response are logged at zap.ErrorLevel.
+// Headers and bodies are redacted and truncated, the headers of the request are not modified.
+// The level of the entry is chosen by the status code:
+//
+//	5xx: zap.ErrorLevel
+//	4xx: zap.WarnLevel
+//	others: the configured level
+func UseRestyLogger(client *resty.Client, opts ...RestyLoggerOption) *resty.Client {
+	config := &RestyLoggerConfig{
+		Level:       defaultRestyLogLevel,
+		MaxBodySize: defaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return client.
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			config.logResponse(resp)
+			return nil
+		}).
+		OnError(func(req *resty.Request, err error) {
+			var respErr *resty.ResponseError
+			if errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.RawResponse != nil {
+				// the call received a response which was already logged by the response hook
+				return
+			}
+			config.logError(req, err)
+		})
+}
+
+// logResponse logs the outbound call based on the resty.Response.
+func (config *RestyLoggerConfig) logResponse(resp *resty.Response) {
+	level := config.Level
+	switch {
+	case resp.StatusCode() >= http.StatusInternalServerError:
+		level = zap.ErrorLevel
+	case resp.StatusCode() >= http.StatusBadRequest:
+		level = zap.WarnLevel
+	}
+	ce := logger.Check(level, restyLogMsg)
+	if ce == nil {
+		return
+	}
+	fields := config.requestFields(resp.Request)
+	fields = append(fields,
+		zap.Int(FieldStatus, resp.StatusCode()),
+		millis(FieldDuration, resp.Time()),
+	)
+	if config.MaxBodySize > 0 {
+		fields = append(fields, zap.String(FieldResponseBody, truncate(redactor.Load().body(resp.Body()), config.MaxBodySize)))
+	}
+	ce.Write(fields...)
+}
+
+// logError logs an outbound call that failed without a response.
+func (config *RestyLoggerConfig) logError(req *resty.Request, err error) {
+	ce := logger.Check(zap.ErrorLevel, restyLogMsg)
+	if ce == nil {
+		return
+	}
+	fields := config.requestFields(req)
+	if !req.Time.IsZero() {
+		fields = append(fields, millis(FieldDuration, time.Since(req.Time)))
+	}
+	fields = append(fields, zap.Error(err))
+	ce.Write(fields...)
+}
+
+// requestFields returns the log fields that describe the outbound request.
+func (config *RestyLoggerConfig) requestFields(req *resty.Request) []zapcore.Field {
+	fields := []zapcore.Field{
+		zap.String(FieldMethod, req.Method),
+		zap.String(FieldURL, redactor.Load().url(req.URL)),
+		zap.Int(FieldAttempt, req.Attempt),
+		zap.String(FieldTraceId, req.Header.Get(defaultTraceIdHeader)),
+		zap.Any(FieldRequestHeaders, redactor.Load().header(req.Header)),
+	}
+	if config.MaxBodySize > 0 && req.Body != nil {
+		fields = append(fields, zap.String(FieldRequestBody, truncate(redactor.Load().requestBody(req.Body), config.MaxBodySize)))
+	}
+	return fields
+}
+
+// truncate shortens s to at most size bytes without splitting a UTF-8 encoded character.
+func truncate(s string, size int) string {
+	if len(s) <= size {
+		return s
+	}
+	for size > 0 && !utf8.RuneStart(s[size]) {
+		size--
+	}
+	return s[:size] + truncatedSuffix
+}
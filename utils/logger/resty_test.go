@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newMockRestyClient(opts ...RestyLoggerOption) *resty.Client {
+	client := resty.New().SetBaseURL(baseUrl)
+	httpmock.ActivateNonDefault(client.GetClient())
+	return UseRestyLogger(client, opts...)
+}
+
+func TestUseRestyLogger(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		configureMockLogger(LevelDebug)
+		client := newMockRestyClient()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodPost, baseUrl+"/login",
+			httpmock.NewStringResponder(http.StatusOK, `{"token":"abc","name":"test"}`))
+
+		resp, err := client.R().
+			SetAuthToken("secret-token").
+			SetHeader(defaultTraceIdHeader, "trace").
+			SetBody(map[string]string{"username": "admin", "password": "admin"}).
+			Post("/login?access_token=xyz")
+		assert.NoError(t, err)
+
+		output := Sink.String()
+		t.Logf("output = %s", output)
+
+		assert.Equal(t, 1, strings.Count(output, restyLogMsg))
+		assert.True(t, strings.Contains(output, `"level":"debug"`))
+		assert.True(t, strings.Contains(output, `"method":"POST"`))
+		assert.True(t, strings.Contains(output, `"url":"https://test.com/login?access_token=[REDACTED]"`))
+		assert.True(t, strings.Contains(output, `"status":200`))
+		assert.True(t, strings.Contains(output, `"attempt":1`))
+		assert.True(t, strings.Contains(output, `"trace-id":"trace"`))
+		assert.True(t, strings.Contains(output, `"duration":`))
+		assert.True(t, strings.Contains(output, `\"password\":\"[REDACTED]\"`))
+		assert.True(t, strings.Contains(output, `\"token\":\"[REDACTED]\"`))
+		assert.False(t, strings.Contains(output, "secret-token"))
+		assert.False(t, strings.Contains(output, "xyz"))
+		assert.Equal(t, "Bearer secret-token", resp.Request.Header.Get("Authorization"))
+	})
+
+	t.Run("status levels", func(t *testing.T) {
+		configureMockLogger(LevelInfo)
+		client := newMockRestyClient(WithRestyLogLevel(zap.InfoLevel))
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/ok", httpmock.NewStringResponder(http.StatusOK, ""))
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/bad", httpmock.NewStringResponder(http.StatusBadRequest, ""))
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/fail", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+		_, _ = client.R().Get("/ok")
+		_, _ = client.R().Get("/bad")
+		_, _ = client.R().Get("/fail")
+
+		output := Sink.String()
+		t.Logf("output = %s", output)
+
+		assert.True(t, strings.Contains(output, `"level":"info"`))
+		assert.True(t, strings.Contains(output, `"level":"warn"`))
+		assert.True(t, strings.Contains(output, `"level":"error"`))
+	})
+
+	t.Run("truncated bodies", func(t *testing.T) {
+		configureMockLogger(LevelDebug)
+		client := newMockRestyClient(WithMaxBodySize(4))
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/", httpmock.NewStringResponder(http.StatusOK, "someString"))
+
+		_, _ = client.R().Get("/")
+
+		output := Sink.String()
+		t.Logf("output = %s", output)
+
+		assert.True(t, strings.Contains(output, `"response-body":"some`+truncatedSuffix+`"`))
+	})
+
+	t.Run("no bodies", func(t *testing.T) {
+		configureMockLogger(LevelDebug)
+		client := newMockRestyClient(WithMaxBodySize(0))
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/", httpmock.NewStringResponder(http.StatusOK, "someString"))
+
+		_, _ = client.R().Get("/")
+
+		output := Sink.String()
+		assert.False(t, strings.Contains(output, FieldResponseBody))
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		configureMockLogger(LevelDebug)
+		client := newMockRestyClient()
+		defer httpmock.DeactivateAndReset()
+		httpmock.RegisterResponder(http.MethodGet, baseUrl+"/", httpmock.NewErrorResponder(errors.New("connection refused")))
+
+		_, err := client.R().Get("/")
+		assert.Error(t, err)
+
+		output := Sink.String()
+		t.Logf("output = %s", output)
+
+		assert.Equal(t, 1, strings.Count(output, restyLogMsg))
+		assert.True(t, strings.Contains(output, `"level":"error"`))
+		assert.True(t, strings.Contains(output, "connection refused"))
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "body", truncate("body", 4))
+	assert.Equal(t, "bo"+truncatedSuffix, truncate("body", 2))
+	// "é" is encoded with 2 bytes and is not split
+	assert.Equal(t, "caf"+truncatedSuffix, truncate("café!", 4))
+	assert.Equal(t, "café"+truncatedSuffix, truncate("café!", 5))
+	assert.True(t, utf8.ValidString(truncate("€€€", 5)))
+}
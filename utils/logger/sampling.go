@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	suppressedSummaryMsg = "Suppressed log entries"
+)
+
+var (
+	sampledCount  atomic.Int64
+	filteredCount atomic.Int64
+
+	// summaryMu guards the summary interval and the stop channel of the running summary.
+	summaryMu       sync.Mutex
+	summaryInterval time.Duration
+	stopSummary     chan struct{}
+)
+
+// SamplingConfig defines how log entries are sampled.
+// Per message and per second the first Initial entries are logged, after which every Thereafter-th entry is logged.
+// A Thereafter value of 0 drops all entries after the first Initial entries.
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+}
+
+// WithSampling is an option that can be used to enable sampling of log entries.
+// Sampling is disabled again if the Initial value is 0.
+func WithSampling(sampling SamplingConfig) Option {
+	return func(config *Config) {
+		config.Sampling = &sampling
+	}
+}
+
+// WithSummaryInterval is an option that can be used to periodically log a summary of the log entries
+// that were dropped by sampling or filtered by GinZap. The summary is disabled if the interval is 0.
+func WithSummaryInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.SummaryInterval = &interval
+	}
+}
+
+// setSampling updates the sampling configuration of the default zap config.
+func setSampling(sampling *SamplingConfig) {
+	if sampling.Initial <= 0 {
+		loggerConfig.Sampling = nil
+		return
+	}
+	loggerConfig.Sampling = &zap.SamplingConfig{
+		Initial:    sampling.Initial,
+		Thereafter: sampling.Thereafter,
+		Hook:       countSampled,
+	}
+}
+
+// countSampled is a sampling hook that counts the log entries that were dropped.
+func countSampled(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped > 0 {
+		sampledCount.Add(1)
+	}
+}
+
+// countFiltered counts a log entry that was filtered.
+func countFiltered() {
+	filteredCount.Add(1)
+}
+
+// setSummaryInterval sets the interval of the summary that is started by startSummary.
+func setSummaryInterval(interval time.Duration) {
+	summaryMu.Lock()
+	defer summaryMu.Unlock()
+	summaryInterval = interval
+}
+
+// startSummary stops any running summary and starts logging a summary at every summary interval using the logger l.
+func startSummary(l *zap.Logger) {
+	summaryMu.Lock()
+	defer summaryMu.Unlock()
+	if stopSummary != nil {
+		close(stopSummary)
+		stopSummary = nil
+	}
+	interval := summaryInterval
+	if interval <= 0 {
+		return
+	}
+	stop := make(chan struct{})
+	stopSummary = stop
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				emitSummary(l)
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// emitSummary logs the number of suppressed log entries since the last summary.
+// Nothing is logged if no entries were suppressed.
+func emitSummary(l *zap.Logger) {
+	sampled := sampledCount.Swap(0)
+	filtered := filteredCount.Swap(0)
+	if sampled == 0 && filtered == 0 {
+		return
+	}
+	l.Info(suppressedSummaryMsg,
+		zap.Int64("sampled", sampled),
+		zap.Int64("filtered", filtered),
+	)
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestWithSampling(t *testing.T) {
+	defer SetLogger(WithSampling(SamplingConfig{}))
+
+	configureMockLogger(LevelInfo)
+	sampledCount.Store(0)
+	SetLogger(WithSampling(SamplingConfig{Initial: 2, Thereafter: 0}))
+
+	for i := 0; i < 5; i++ {
+		Info("sampled message")
+	}
+	Info("other message")
+
+	output := Sink.String()
+	t.Logf("output = %s", output)
+
+	assert.Equal(t, 2, strings.Count(output, "sampled message"))
+	assert.Equal(t, 1, strings.Count(output, "other message"))
+	assert.Equal(t, int64(3), sampledCount.Load())
+}
+
+func TestSetLoggerWithConfigSampling(t *testing.T) {
+	defer SetLogger()
+
+	configureMockLogger(LevelInfo)
+	config := loggerConfig
+	config.Sampling = &zap.SamplingConfig{Initial: 1}
+	SetLoggerWithConfig(config)
+
+	for i := 0; i < 3; i++ {
+		Info("sampled message")
+	}
+	assert.Equal(t, 1, strings.Count(Sink.String(), "sampled message"))
+}
+
+func TestWithSamplingDisabled(t *testing.T) {
+	SetLogger(WithSampling(SamplingConfig{Initial: 1}))
+	assert.NotNil(t, loggerConfig.Sampling)
+
+	SetLogger(WithSampling(SamplingConfig{}))
+	assert.Nil(t, loggerConfig.Sampling)
+}
+
+func TestEmitSummary(t *testing.T) {
+	configureMockLogger(LevelInfo)
+
+	t.Run("nothing suppressed", func(t *testing.T) {
+		sampledCount.Store(0)
+		filteredCount.Store(0)
+		emitSummary(logger)
+		assert.False(t, strings.Contains(Sink.String(), suppressedSummaryMsg))
+	})
+
+	t.Run("suppressed", func(t *testing.T) {
+		sampledCount.Store(3)
+		filteredCount.Store(2)
+		emitSummary(logger)
+
+		output := Sink.String()
+		t.Logf("output = %s", output)
+
+		assert.True(t, strings.Contains(output, suppressedSummaryMsg))
+		assert.True(t, strings.Contains(output, `"sampled":3`))
+		assert.True(t, strings.Contains(output, `"filtered":2`))
+		assert.Equal(t, int64(0), sampledCount.Load())
+		assert.Equal(t, int64(0), filteredCount.Load())
+	})
+}
+
+func TestWithSummaryInterval(t *testing.T) {
+	defer SetLogger(WithSummaryInterval(0))
+
+	SetLogger(WithSummaryInterval(time.Hour))
+	assert.Equal(t, time.Hour, summaryInterval)
+	assert.NotNil(t, stopSummary)
+
+	SetLogger(WithSummaryInterval(0))
+	assert.Equal(t, time.Duration(0), summaryInterval)
+	assert.Nil(t, stopSummary)
+}
+
+func TestStartSummaryConcurrent(t *testing.T) {
+	defer SetLogger(WithSummaryInterval(0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			setSummaryInterval(time.Hour)
+			startSummary(logger)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		setSummaryInterval(0)
+		startSummary(logger)
+	}
+	<-done
+}
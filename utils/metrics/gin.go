@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware is a gin middleware that records the number, duration and response size of the requests
+// and the number of requests in flight. The requests are labelled by method, route template and status class.
+// The route template (gin FullPath) is used instead of the path to keep the number of label values bounded,
+// requests that did not match a route are labelled as UnmatchedRoute.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		m.requestsInFlight.Inc()
+		defer m.requestsInFlight.Dec()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = UnmatchedRoute
+		}
+		status := statusClass(ctx.Writer.Status())
+		method := ctx.Request.Method
+
+		m.requests.WithLabelValues(method, route, status).Inc()
+		m.requestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+		size := ctx.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+		m.responseSize.WithLabelValues(method, route, status).Observe(float64(size))
+	}
+}
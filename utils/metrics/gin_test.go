@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := New(WithRegistry(prometheus.NewRegistry()))
+	r := newRouter(m)
+
+	for _, path := range []string{"/users/1", "/users/2", "/unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(w, req)
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/users/:id", "2xx")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, UnmatchedRoute, "4xx")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.requestsInFlight))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.requestDuration))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.responseSize))
+}
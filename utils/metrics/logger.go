@@ -0,0 +1,14 @@
+package metrics
+
+import (
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerHook returns a hook that counts the log entries per level.
+// The hook can be registered with logger.SetLogger(logger.WithHooks(m.LoggerHook())).
+func (m *Metrics) LoggerHook() func(zapcore.Entry) error {
+	return func(entry zapcore.Entry) error {
+		m.logEntries.WithLabelValues(entry.Level.String()).Inc()
+		return nil
+	}
+}
@@ -0,0 +1,23 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/atselvan/go-utils/utils/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_LoggerHook(t *testing.T) {
+	m := New(WithRegistry(prometheus.NewRegistry()))
+	logger.SetLogger(logger.WithHooks(m.LoggerHook()))
+	defer logger.SetLogger(logger.WithHooks())
+
+	logger.Info("info message")
+	logger.Error("error message")
+	logger.Error("error message")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.logEntries.WithLabelValues("info")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.logEntries.WithLabelValues("error")))
+}
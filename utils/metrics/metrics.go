@@ -0,0 +1,175 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	LabelMethod      = "method"
+	LabelRoute       = "route"
+	LabelStatusClass = "status_class"
+	LabelHost        = "host"
+	LabelLevel       = "level"
+
+	// UnmatchedRoute is the route label of requests that did not match a route.
+	UnmatchedRoute = "unmatched"
+	// StatusClassError is the status class label of outbound calls that failed without a response.
+	StatusClassError = "error"
+)
+
+var (
+	// DefaultSizeBuckets are the buckets of the response size histograms in bytes.
+	DefaultSizeBuckets = prometheus.ExponentialBuckets(100, 10, 6)
+)
+
+type (
+	// Config represents the configuration of the Metrics.
+	Config struct {
+		Namespace       string
+		Registry        *prometheus.Registry
+		DurationBuckets []float64
+		SizeBuckets     []float64
+	}
+
+	// Option is an option that can be used to configure the Metrics.
+	Option func(config *Config)
+
+	// Metrics keeps the Prometheus collectors of a service.
+	Metrics struct {
+		registry *prometheus.Registry
+
+		requests         *prometheus.CounterVec
+		requestDuration  *prometheus.HistogramVec
+		responseSize     *prometheus.HistogramVec
+		requestsInFlight prometheus.Gauge
+
+		clientRequests        *prometheus.CounterVec
+		clientRequestDuration *prometheus.HistogramVec
+
+		logEntries *prometheus.CounterVec
+	}
+)
+
+// WithNamespace is an option that prefixes the names of the metrics with the namespace.
+func WithNamespace(namespace string) Option {
+	return func(config *Config) {
+		config.Namespace = namespace
+	}
+}
+
+// WithRegistry is an option that defines the registry the metrics are registered with.
+// By default, a new registry with the Go and process collectors is created.
+func WithRegistry(registry *prometheus.Registry) Option {
+	return func(config *Config) {
+		config.Registry = registry
+	}
+}
+
+// WithDurationBuckets is an option that defines the buckets of the duration histograms in seconds.
+// Default buckets are prometheus.DefBuckets.
+func WithDurationBuckets(buckets ...float64) Option {
+	return func(config *Config) {
+		config.DurationBuckets = buckets
+	}
+}
+
+// WithSizeBuckets is an option that defines the buckets of the response size histogram in bytes.
+// Default buckets are DefaultSizeBuckets.
+func WithSizeBuckets(buckets ...float64) Option {
+	return func(config *Config) {
+		config.SizeBuckets = buckets
+	}
+}
+
+// New creates the collectors and registers them with the registry.
+func New(opts ...Option) *Metrics {
+	config := &Config{
+		DurationBuckets: prometheus.DefBuckets,
+		SizeBuckets:     DefaultSizeBuckets,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Registry == nil {
+		config.Registry = prometheus.NewRegistry()
+		config.Registry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+	}
+
+	labels := []string{LabelMethod, LabelRoute, LabelStatusClass}
+	clientLabels := []string{LabelMethod, LabelHost, LabelStatusClass}
+	m := &Metrics{
+		registry: config.Registry,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests.",
+		}, labels),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests in seconds.",
+			Buckets:   config.DurationBuckets,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "http_response_size_bytes",
+			Help:      "Size of HTTP responses in bytes.",
+			Buckets:   config.SizeBuckets,
+		}, labels),
+		requestsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: config.Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests that are being served.",
+		}),
+		clientRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "http_client_requests_total",
+			Help:      "Total number of outbound HTTP requests.",
+		}, clientLabels),
+		clientRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: config.Namespace,
+			Name:      "http_client_request_duration_seconds",
+			Help:      "Duration of outbound HTTP requests in seconds.",
+			Buckets:   config.DurationBuckets,
+		}, clientLabels),
+		logEntries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: config.Namespace,
+			Name:      "log_entries_total",
+			Help:      "Total number of log entries.",
+		}, []string{LabelLevel}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.requestDuration,
+		m.responseSize,
+		m.requestsInFlight,
+		m.clientRequests,
+		m.clientRequestDuration,
+		m.logEntries,
+	)
+	return m
+}
+
+// Registry returns the registry of the metrics, which can be used to register custom collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler returns a gin handler that serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+	return gin.WrapH(h)
+}
+
+// statusClass returns the class of the status code, for example 2xx.
+func statusClass(status int) string {
+	return strconv.Itoa(status/100) + "xx"
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRouter(m *Metrics) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/metrics", m.Handler())
+	r.GET("/users/:id", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, "OK")
+	})
+	return r
+}
+
+func TestNew(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		m := New()
+		assert.NotNil(t, m.Registry())
+
+		families, err := m.Registry().Gather()
+		assert.NoError(t, err)
+		names := make([]string, 0, len(families))
+		for _, f := range families {
+			names = append(names, f.GetName())
+		}
+		assert.Contains(t, names, "go_goroutines")
+		assert.Contains(t, names, "http_requests_in_flight")
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		m := New(WithNamespace("test"), WithRegistry(registry), WithDurationBuckets(0.1, 1), WithSizeBuckets(10))
+		assert.Equal(t, registry, m.Registry())
+
+		families, err := registry.Gather()
+		assert.NoError(t, err)
+		assert.Len(t, families, 1)
+		assert.Equal(t, "test_http_requests_in_flight", families[0].GetName())
+	})
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	r := newRouter(New(WithRegistry(prometheus.NewRegistry())))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	r.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/metrics", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain"))
+	assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="/users/:id",status_class="2xx"} 1`)
+}
+
+func TestStatusClass(t *testing.T) {
+	assert.Equal(t, "2xx", statusClass(http.StatusOK))
+	assert.Equal(t, "4xx", statusClass(http.StatusNotFound))
+	assert.Equal(t, "5xx", statusClass(http.StatusServiceUnavailable))
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// InstrumentResty attaches hooks to the resty client that record the number and duration of the outbound calls,
+// labelled by method, host and status class. Calls that failed without a response are labelled as StatusClassError.
+func (m *Metrics) InstrumentResty(client *resty.Client) *resty.Client {
+	return client.
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			m.observeClientRequest(resp.Request, statusClass(resp.StatusCode()), resp.Time())
+			return nil
+		}).
+		OnError(func(req *resty.Request, err error) {
+			var respErr *resty.ResponseError
+			if errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.RawResponse != nil {
+				// the call received a response which was already recorded by the response hook
+				return
+			}
+			var duration time.Duration
+			if !req.Time.IsZero() {
+				duration = time.Since(req.Time)
+			}
+			m.observeClientRequest(req, StatusClassError, duration)
+		})
+}
+
+// observeClientRequest records an outbound call.
+func (m *Metrics) observeClientRequest(req *resty.Request, status string, duration time.Duration) {
+	var host string
+	if u, err := url.Parse(req.URL); err == nil {
+		host = u.Host
+	}
+	m.clientRequests.WithLabelValues(req.Method, host, status).Inc()
+	m.clientRequestDuration.WithLabelValues(req.Method, host, status).Observe(duration.Seconds())
+}
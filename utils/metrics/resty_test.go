@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_InstrumentResty(t *testing.T) {
+	m := New(WithRegistry(prometheus.NewRegistry()))
+	client := m.InstrumentResty(resty.New())
+	httpmock.ActivateNonDefault(client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/ok", httpmock.NewStringResponder(http.StatusOK, "OK"))
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/error", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	httpmock.RegisterResponder(http.MethodGet, "https://test.com/failure", httpmock.NewErrorResponder(fmt.Errorf("connection refused")))
+
+	_, _ = client.R().Get("https://test.com/ok")
+	_, _ = client.R().Get("https://test.com/error")
+	_, _ = client.R().Get("https://test.com/failure")
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.clientRequests.WithLabelValues(http.MethodGet, "test.com", "2xx")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.clientRequests.WithLabelValues(http.MethodGet, "test.com", "5xx")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.clientRequests.WithLabelValues(http.MethodGet, "test.com", StatusClassError)))
+	assert.Equal(t, 3, testutil.CollectAndCount(m.clientRequestDuration))
+}
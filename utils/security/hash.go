@@ -0,0 +1,104 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/atselvan/go-utils/utils/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2idPrefix    = "$argon2id$"
+	argon2idFormat    = "$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s"
+	argon2idMemory    = 64 * 1024
+	argon2idTime      = 3
+	argon2idThreads   = 4
+	argon2idKeyLength = 32
+	argon2idSaltSize  = 16
+)
+
+// HashPassword hashes the password using bcrypt with the default cost.
+func HashPassword(password string) (string, *errors.Error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Newf(
+			errors.ErrCodePasswordHashError,
+			0,
+			errors.ErrMsg[errors.ErrCodePasswordHashError],
+			err.Error(),
+		)
+	}
+	return string(hash), nil
+}
+
+// HashPasswordArgon2id hashes the password using argon2id and returns the hash in the PHC string format.
+func HashPasswordArgon2id(password string) (string, *errors.Error) {
+	salt := make([]byte, argon2idSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Newf(
+			errors.ErrCodePasswordHashError,
+			0,
+			errors.ErrMsg[errors.ErrCodePasswordHashError],
+			err.Error(),
+		)
+	}
+	key := argon2.IDKey([]byte(password), salt, argon2idTime, argon2idMemory, argon2idThreads, argon2idKeyLength)
+	return fmt.Sprintf(argon2idFormat,
+		argon2.Version, argon2idMemory, argon2idTime, argon2idThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// ComparePasswordHash checks if the password matches the hash.
+// Both bcrypt hashes and argon2id hashes in the PHC string format are supported.
+// The function returns false if the hash format is not supported.
+func ComparePasswordHash(hash, password string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return compareArgon2idHash(hash, password)
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// compareArgon2idHash checks if the password matches an argon2id hash in the PHC string format.
+func compareArgon2idHash(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return false
+	}
+	var (
+		version      int
+		memory, time uint32
+		threads      uint8
+	)
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil ||
+		time == 0 || threads == 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// CompareSecrets compares two secrets in constant time.
+// The comparison does not leak the length of the secrets.
+func CompareSecrets(a, b string) bool {
+	ha := createSHA256Hash(a)
+	hb := createSHA256Hash(b)
+	return subtle.ConstantTimeCompare(ha, hb) == 1
+}
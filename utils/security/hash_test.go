@@ -0,0 +1,49 @@
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, cErr := HashPassword(password)
+	assert.Nil(t, cErr)
+	assert.True(t, strings.HasPrefix(hash, "$2a$"))
+	assert.True(t, ComparePasswordHash(hash, password))
+	assert.False(t, ComparePasswordHash(hash, "other"))
+}
+
+func TestHashPasswordArgon2id(t *testing.T) {
+	hash, cErr := HashPasswordArgon2id(password)
+	assert.Nil(t, cErr)
+	assert.True(t, strings.HasPrefix(hash, argon2idPrefix))
+	assert.True(t, ComparePasswordHash(hash, password))
+	assert.False(t, ComparePasswordHash(hash, "other"))
+
+	other, cErr := HashPasswordArgon2id(password)
+	assert.Nil(t, cErr)
+	assert.NotEqual(t, hash, other)
+}
+
+func TestComparePasswordHash(t *testing.T) {
+	t.Run("unsupported hash", func(t *testing.T) {
+		assert.False(t, ComparePasswordHash(password, password))
+	})
+
+	t.Run("invalid argon2id hash", func(t *testing.T) {
+		assert.False(t, ComparePasswordHash("$argon2id$v=19$m=65536,t=3,p=4$salt", password))
+		assert.False(t, ComparePasswordHash("$argon2id$v=18$m=65536,t=3,p=4$c2FsdA$aGFzaA", password))
+		assert.False(t, ComparePasswordHash("$argon2id$v=19$m=x$c2FsdA$aGFzaA", password))
+		assert.False(t, ComparePasswordHash("$argon2id$v=19$m=65536,t=3,p=0$c2FsdA$aGFzaA", password))
+		assert.False(t, ComparePasswordHash("$argon2id$v=19$m=65536,t=3,p=4$!$aGFzaA", password))
+		assert.False(t, ComparePasswordHash("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$!", password))
+	})
+}
+
+func TestCompareSecrets(t *testing.T) {
+	assert.True(t, CompareSecrets(password, password))
+	assert.False(t, CompareSecrets(password, "other"))
+	assert.False(t, CompareSecrets(password, ""))
+}
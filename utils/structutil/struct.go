@@ -1,8 +1,14 @@
 package structutil
 
 import (
+	"fmt"
+	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/atselvan/go-utils/utils/errors"
 )
 
 const (
@@ -10,6 +16,7 @@ const (
 	StructTagMapstructure = "mapstructure"
 	StructTagJson         = "json"
 	StructTagYaml         = "yaml"
+	StructTagDefault      = "default"
 )
 
 // GetFieldTagValue returns the value of the mapstructure field tag for a struct field.
@@ -53,3 +60,99 @@ func GetYamlFieldTagValue(structPointer any, fieldPointer any) string {
 	}
 	return strings.Split(tagValue, ",")[0]
 }
+
+// SetDefaults sets the fields of a struct to the value of their default tag, for example `default:"10"`.
+// The defaults are applied to strings, booleans, numbers, durations and slices of those, which are defined as a
+// comma separated list. Nested structs are handled recursively.
+// The method should be called before the struct is decoded, so that the defaults are only kept for the fields
+// that are not decoded. An error is returned if a default value cannot be parsed.
+func SetDefaults(structPointer any) *errors.Error {
+	v := reflect.ValueOf(structPointer)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	return setDefaults(v.Elem())
+}
+
+// setDefaults sets the defaults of the fields of the struct value.
+func setDefaults(v reflect.Value) *errors.Error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if def, ok := field.Tag.Lookup(StructTagDefault); ok {
+			if err := setValue(fv, def); err != nil {
+				return errors.Newf(errors.ErrCodeInvalidStructTag, http.StatusInternalServerError, errors.ErrMsg[errors.ErrCodeInvalidStructTag],
+					StructTagDefault, field.Name, err.Error())
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := setDefaults(fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setValue parses the value and sets it on the field.
+func setValue(fv reflect.Value, value string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if fv.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+			fv.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		values := strings.Split(value, ",")
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, e := range values {
+			if err := setValue(slice.Index(i), strings.TrimSpace(e)); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+	case reflect.Pointer:
+		ptr := reflect.New(fv.Type().Elem())
+		if err := setValue(ptr.Elem(), value); err != nil {
+			return err
+		}
+		fv.Set(ptr)
+	default:
+		return fmt.Errorf("type %s is not supported", fv.Type())
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package structutil
 
 import (
+	"net/http"
+
+	"github.com/atselvan/go-utils/utils/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 type User struct {
@@ -26,3 +30,59 @@ func TestGetYamlFieldTagValue(t *testing.T) {
 	tag := GetYamlFieldTagValue(user, &user.Name)
 	assert.Equal(t, "name_y", tag)
 }
+
+type Defaults struct {
+	Name    string        `default:"test"`
+	Enabled bool          `default:"true"`
+	Limit   int           `default:"10"`
+	Max     uint8         `default:"255"`
+	Ratio   float64       `default:"0.5"`
+	Timeout time.Duration `default:"1m"`
+	Tags    []string      `default:"a, b"`
+	Page    *int          `default:"1"`
+	Nested  struct {
+		Size int `default:"20"`
+	}
+	NoDefault string
+	internal  string `default:"internal"`
+}
+
+func TestSetDefaults(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		d := Defaults{NoDefault: "keep"}
+		assert.Nil(t, SetDefaults(&d))
+		assert.Equal(t, "test", d.Name)
+		assert.True(t, d.Enabled)
+		assert.Equal(t, 10, d.Limit)
+		assert.Equal(t, uint8(255), d.Max)
+		assert.Equal(t, 0.5, d.Ratio)
+		assert.Equal(t, time.Minute, d.Timeout)
+		assert.Equal(t, []string{"a", "b"}, d.Tags)
+		assert.Equal(t, 1, *d.Page)
+		assert.Equal(t, 20, d.Nested.Size)
+		assert.Equal(t, "keep", d.NoDefault)
+		assert.Equal(t, "", d.internal)
+	})
+
+	t.Run("not a struct pointer", func(t *testing.T) {
+		assert.Nil(t, SetDefaults(Defaults{}))
+	})
+
+	t.Run("invalid default", func(t *testing.T) {
+		d := struct {
+			Limit int `default:"ten"`
+		}{}
+		err := SetDefaults(&d)
+		assert.NotNil(t, err)
+		assert.Equal(t, errors.ErrCodeInvalidStructTag, err.Code)
+		assert.Equal(t, http.StatusInternalServerError, err.Status)
+		assert.Contains(t, err.Message, "Limit")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		d := struct {
+			Labels map[string]string `default:"a"`
+		}{}
+		assert.NotNil(t, SetDefaults(&d))
+	})
+}
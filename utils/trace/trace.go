@@ -0,0 +1,23 @@
+// Package trace keeps the trace id of a request, so that it can be passed on to the requests of clients
+// without depending on the HTTP server utilities.
+package trace
+
+import "context"
+
+const (
+	HeaderKey = "Trace-Id"
+)
+
+// ctxKey is the key of the trace id in the context.
+type ctxKey struct{}
+
+// NewContext returns a copy of the context that contains the trace id.
+func NewContext(ctx context.Context, traceId string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, traceId)
+}
+
+// FromContext returns the trace id that is stored in the context or an empty string if it is not set.
+func FromContext(ctx context.Context) string {
+	traceId, _ := ctx.Value(ctxKey{}).(string)
+	return traceId
+}
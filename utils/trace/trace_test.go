@@ -0,0 +1,18 @@
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("trace id", func(t *testing.T) {
+		assert.Equal(t, "trace", FromContext(NewContext(context.Background(), "trace")))
+	})
+
+	t.Run("no trace id", func(t *testing.T) {
+		assert.Empty(t, FromContext(context.Background()))
+	})
+}